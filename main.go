@@ -1,20 +1,46 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
 	"runtime"
+	"maps"
+	"math"
+	"net"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/build"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 )
 
 // viewMode represents different views in the TUI
@@ -26,6 +52,17 @@ const (
 	viewLogs
 	viewShell
 	viewSearch
+	viewStats
+	viewImages
+	viewVolumes
+	viewNetworks
+	viewDiskUsage
+	viewPull
+	viewRun
+	viewRunCommand
+	viewFiles
+	viewDiff
+	viewTop
 )
 
 // Color palette and styles
@@ -101,6 +138,15 @@ var (
 	// Divider style
 	dividerStyle = lipgloss.NewStyle().
 		Foreground(mutedColor)
+
+	// Log lines written to stderr
+	stderrStyle = lipgloss.NewStyle().
+		Foreground(errorColor)
+
+	// Paused container state
+	pausedStyle = lipgloss.NewStyle().
+		Foreground(warningColor).
+		Bold(true)
 )
 
 // Model represents the TUI application state
@@ -115,29 +161,218 @@ type Model struct {
 	statusMsg    string
 	currentView  viewMode
 	inspectData  string
-	logsData     string
 	socketPath   string // Track which socket we connected to
 	hideK8s      bool   // Toggle to hide k8s_ containers
 	hideExited   bool   // Toggle to hide exited containers
+	hidePaused   bool   // Toggle to hide paused containers
 	width        int    // Terminal width
 	height       int    // Terminal height
 
+	// Inspect view state
+	inspectTitle       string                     // Title of the inspect view ("" for containers)
+	inspectReturn      viewMode                   // View to go back to from the inspect view
+	inspectInfo        *container.InspectResponse // Inspected container, nil when inspecting an image
+	inspectTab         int                        // Selected tab of inspectTabNames()
+	inspectOffset      int                        // First line of the tab shown
+	inspectQuery       string                     // Query applied to the raw JSON tab
+	inspectQueryLines  []string                   // Result of inspectQuery
+	inspectQueryPrompt bool                       // Whether the query box is open
+	inspectQueryInput  string                     // Text typed into the query box
+
 	// Shell popup state
-	shellOutput       []string // Lines of shell output
-	shellInput        string   // Current input line
-	shellContainerID  string   // Container ID for shell session
-	shellContainerName string  // Container name for display
-	shellExecID       string   // Docker exec session ID
-	shellScroll       int      // Scroll position in shell output
+	shellInput         string                  // Keystrokes typed before the exec session is attached
+	shellContainerID   string                  // Container ID for shell session
+	shellContainerName string                  // Container name for display
+	shellExecID        string                  // Docker exec session ID
+	shellSessionID     int                     // Incremented per opened shell so stale sessions are dropped
+	shellConn          *types.HijackedResponse // Attached TTY stream (nil until ready)
+	shellScreen        *terminalScreen         // Terminal emulator fed by the TTY output
 
 	// Fuzzy search state
 	searchInput   string         // Current search query
 	searchResults []searchResult // Filtered search results
 	searchCursor  int            // Selected result index
 
+	// Multi-select state
+	selected          map[string]bool // Container IDs selected for multi-container actions
+	selectPrompt      bool            // Whether the select-by-filter prompt is open
+	selectPromptInput string          // Text typed into the select-by-filter prompt
+	killPicker        bool            // Whether the kill signal picker is open
+	killSignal        int             // Index into killSignals
+	killTargets       []containerInfo // Containers the picked signal is sent to
+
+	// File copy state
+	copyStage         copyStage          // Which copy prompt is open, if any
+	copyInto          bool               // Whether files go into the container (else out of it)
+	copyInput         string             // Text typed into the copy prompt
+	copyTarget        containerInfo      // Container files are copied to or from
+	copyContainerPath string             // Validated path in the container
+	copyStat          container.PathStat // ContainerStatPath result for copyContainerPath
+	copyID            int                // Incremented per copy so stale messages are dropped
+	copyCh            chan tea.Msg       // Receives copy progress from the copy goroutine
+	copyRunning       bool               // Whether a copy is in progress
+	copyLabel         string             // "web:/etc/nginx → ./", for the status bar
+
+	// File browser state
+	filesContainer     containerInfo // Container being browsed
+	filesPath          string        // Directory being listed
+	filesEntries       []fileEntry   // Contents of filesPath
+	filesCursor        int           // Selected entry
+	filesLoading       bool          // Whether a listing is in progress
+	filesPreviewing    bool          // Whether a file preview is shown instead of the listing
+	filesPreviewPath   string        // File being previewed
+	filesPreviewLines  []string      // Start of the previewed file
+	filesPreviewNote   string        // Shown above the preview, e.g. for binary or truncated files
+	filesPreviewOffset int           // First preview line shown
+	filesID            int           // Incremented per listing or preview so stale results are dropped
+
+	// Filesystem diff state
+	diffContainer containerInfo   // Container whose changes are shown
+	diffRoot      *diffNode       // Changes arranged as a tree
+	diffCollapsed map[string]bool // Directories whose changes are hidden
+	diffCursor    int             // Selected row of the visible tree
+	diffLoading   bool            // Whether ContainerDiff is in progress
+	diffID        int             // Incremented per load so stale results are dropped
+
+	// Process list state
+	topContainer    containerInfo // Container whose processes are shown
+	topArgs         string        // ps arguments passed to ContainerTop
+	topTitles       []string      // ps column titles
+	topProcesses    [][]string    // ps rows, sorted by topSort
+	topCursor       int           // Selected process row
+	topPID          string        // PID of the selected process, followed across refreshes
+	topSort         topSortKey    // Column the processes are sorted by
+	topID           int           // Incremented per refresh cycle so stale ticks are dropped
+	topLoading      bool          // Whether ContainerTop is in progress
+	topArgsPrompt   bool          // Whether the ps arguments prompt is open
+	topArgsInput    string        // Text typed into the ps arguments prompt
+	topSignalPicker bool          // Whether the signal picker is open
+	topSignal       int           // Index into killSignals
+	topSignalPID    string        // PID the picked signal is sent to
+
+	// Compose grouping state
+	groupByProject bool            // Group the list under compose project headers
+	collapsed      map[string]bool // Compose projects whose containers are hidden
+	cursorProject  string          // Project whose header the cursor is on ("" when on a container)
+
 	// Confirmation dialog state
-	confirmingDestroy  bool   // Whether we're in destroy confirmation mode
-	containerToDestroy string // Container ID to destroy if confirmed
+	confirmingDestroy  bool            // Whether we're in destroy confirmation mode
+	containerToDestroy string          // Container ID to destroy if confirmed (single container)
+	destroyTargets     []containerInfo // Every container to destroy if confirmed
+
+	// Bulk operation state
+	bulkOp   *bulkOperation // Operation running across several containers (nil when idle)
+	bulkOpID int            // Identifies the current bulk operation so stale results are dropped
+
+	// Logs view state
+	logsBuffer      *logBuffer         // Most recent log lines (bounded)
+	logsSources     []logSource        // Containers whose logs are shown
+	logsStream      *logStream         // Active follow stream (nil when not streaming)
+	logsCancel      context.CancelFunc // Cancels the active follow stream
+	logsStreamID    int                // Identifies the current stream so stale messages are dropped
+	logsPaused      bool               // Whether consuming the stream is paused
+	logsWaiting     bool               // Whether a waitForLogLines command is in flight
+	logsEnded       bool               // Whether the stream has finished (e.g. container stopped)
+	logsFollow      bool               // Keep the view pinned to the newest line
+	logsTop         int                // Absolute number of the top line when not following
+	logsWrap        bool               // Wrap long lines instead of truncating
+	logsSearch      *regexp.Regexp     // Active search pattern (nil when not searching)
+	logsPrompt      string             // Active input prompt: "search", "tail", "since" or ""
+	logsPromptInput string             // Text typed into the active prompt
+	logsTail        string             // Lines to fetch before following ("all" or a count)
+	logsSince       string             // Only show logs newer than this (duration or timestamp)
+	logsStreams     logStreamFilter    // Which output streams are shown
+	logsTimestamps  bool               // Ask the daemon to prefix lines with timestamps
+
+	// Resource stats state
+	showStats    bool                    // Show CPU/memory/network/block I/O columns
+	statsSort    statsSortKey            // Column the container list is sorted by
+	stats        map[string]statsSample  // Latest sample per container ID
+	statsCh      chan tea.Msg            // statsMsg and statsEndedMsg from every stats stream
+	statsStreams map[string]*statsStream // Open ContainerStats streams by container ID
+
+	// Stats detail view state
+	statsViewID     string        // Container shown in the stats view
+	statsViewName   string        // Container name for display
+	statsViewStream *statsStream  // Stream feeding the view (nil when closed)
+	statsViewCh     chan tea.Msg  // Channel the view's stream delivers to
+	statsHistory    []statsSample // Recent samples, oldest first
+	statsViewEnded  bool          // Whether the stream has finished (e.g. container stopped)
+
+	// Images view state
+	images                []imageInfo // One row per repo:tag (or per dangling image)
+	imagesCursor          int         // Selected image row
+	imagesLoading         bool        // Whether an ImageList request is in flight
+	imageTagPrompt        bool        // Whether the tag prompt is open
+	imageTagInput         string      // Text typed into the tag prompt
+	confirmingImageRemove bool        // Whether we're in image remove confirmation mode
+	imageRemoveForce      bool        // Whether the confirmed remove is forced
+
+	// Image pull view state
+	pullPrompt  bool               // Whether the image reference prompt is open
+	pullInput   string             // Reference typed into the prompt
+	pullRef     string             // Reference being pulled
+	pullID      int                // Incremented per pull so stale messages are dropped
+	pullCancel  context.CancelFunc // Cancels the running pull
+	pullCh      chan tea.Msg       // Receives pull progress from the stream goroutine
+	pullLayers  []pullLayer        // Per-layer progress in first-seen order
+	pullLog     []string           // Status lines not tied to a layer
+	pullRunning bool               // Whether a pull is in progress
+	pullErr     error              // Why the last pull failed, if it did
+	pullReturn  viewMode           // View to go back to
+
+	// Run form state
+	runForm       [runFieldCount]string // Run form input values
+	runFocus      runField              // Focused run form input
+	runSubmitting bool                  // Whether ContainerCreate/Start is in flight
+	runReturn     viewMode              // View to go back to
+	runRecreate   *recreateTarget       // Container being recreated, nil when running a new one
+	runConfirming bool                  // Whether we're asking to confirm a recreate
+
+	// Generated docker run command view state
+	runCommandName string  // Container the command recreates
+	runCommand     runSpec // Options reconstructed from inspect data
+
+	// Compose export state
+	exportPrompt              bool            // Whether the export path prompt is open
+	exportPromptInput         string          // Path typed into the export prompt
+	exportTargets             []containerInfo // Containers to export
+	confirmingExportOverwrite bool            // Whether we're asking to overwrite an existing file
+	exportPath                string          // Resolved path to write
+
+	// Volumes view state
+	volumes                []volumeInfo     // Volumes sorted by name
+	volumesCursor          int              // Selected volume row
+	volumesLoading         bool             // Whether a VolumeList request is in flight
+	volumeSizes            map[string]int64 // Latest DiskUsage sizes, which may arrive before the list
+	confirmingVolumeRemove bool             // Whether we're in volume remove confirmation mode
+	volumeToRemove         string           // Volume name to remove if confirmed
+	confirmingVolumePrune  bool             // Whether we're in volume prune confirmation mode
+
+	// Networks view state
+	networks                []networkInfo // Networks sorted by name
+	networksCursor          int           // Selected network row
+	networksLoading         bool          // Whether a NetworkList request is in flight
+	networkContainer        containerInfo // Container that connect/disconnect act on
+	networkCreatePrompt     bool          // Whether the create prompt is open
+	networkCreateInput      string        // Name typed into the create prompt
+	confirmingNetworkRemove bool          // Whether we're in network remove confirmation mode
+	networkToRemove         string        // Network name to remove if confirmed
+
+	// Disk usage view state
+	diskUsage           []diskUsageRow // Images, containers, volumes and build cache
+	diskCursor          int            // Selected category
+	diskLoading         bool           // Whether a DiskUsage request is in flight
+	confirmingDiskPrune bool           // Whether we're in prune confirmation mode
+	diskPreviewScroll   int            // First prune candidate shown while confirming
+
+	// Docker events stream state
+	eventsCh     <-chan events.Message // Container events from the daemon
+	eventsErrCh  <-chan error          // Receives an error when the stream drops
+	eventsActive bool                  // Whether the events stream is connected
+	eventsSeq    int                   // Incremented per handled container event
+	containerSeq map[string]int        // eventsSeq of the latest event for each container
+	listSeq      int                   // eventsSeq when the last applied full list was requested
 }
 
 // searchResult represents a fuzzy search match
@@ -151,12 +386,17 @@ type searchResult struct {
 
 // containerInfo holds display information about a container
 type containerInfo struct {
-	ID     string
-	Name   string
-	Image  string
-	Status string
-	State  string
-	Ports  []string // Port mappings (e.g., "8080:80/tcp")
+	ID       string
+	Name     string
+	Image    string
+	Status   string
+	State    string
+	Ports    []string          // Port mappings (e.g., "8080:80/tcp")
+	ImageID  string            // Full ID of the container's image
+	Volumes  []string          // Names of mounted volumes
+	Networks map[string]string // IP address by attached network name
+	Project  string            // Docker Compose project label ("" for standalone containers)
+	Service  string            // Docker Compose service label
 }
 
 // containersLoadedMsg is sent when containers are loaded from Docker
@@ -164,6 +404,7 @@ type containersLoadedMsg struct {
 	containers  []containerInfo
 	err         error
 	showRefresh bool // Whether to show "refreshed" message
+	seq         int  // eventsSeq when the list was requested
 }
 
 // operationCompleteMsg is sent when a container operation completes
@@ -174,38 +415,87 @@ type operationCompleteMsg struct {
 
 // inspectDataMsg contains container inspection data
 type inspectDataMsg struct {
-	data string
-	err  error
+	data     string
+	err      error
+	title    string                     // Inspect view title ("" for containers)
+	returnTo viewMode                   // View to go back to when leaving the inspect view
+	info     *container.InspectResponse // Container details for the tabs (nil for images)
 }
 
-// logsDataMsg contains container logs
-type logsDataMsg struct {
-	data string
-	err  error
+// logsStreamMsg is sent when a follow-mode log stream has been opened
+type logsStreamMsg struct {
+	streamID int
+	stream   *logStream
+	err      error
+}
+
+// logLinesMsg carries a batch of new log lines from the active stream
+type logLinesMsg struct {
+	streamID int
+	lines    []logLine
+}
+
+// logsStreamEndedMsg is sent when the log stream closes
+type logsStreamEndedMsg struct {
+	streamID int
+	err      error
 }
 
 // clearStatusMsg is sent to clear the status message
 type clearStatusMsg struct{}
 
-// tickMsg is sent periodically to trigger auto-refresh
+// tickMsg is sent periodically to trigger a full resync while the events stream is down
 type tickMsg time.Time
 
+// eventsSubscribedMsg is sent when a Docker events subscription has been opened
+type eventsSubscribedMsg struct {
+	messages <-chan events.Message
+	errs     <-chan error
+}
+
+// containerEventMsg carries a single container event from the daemon
+type containerEventMsg struct {
+	event events.Message
+}
+
+// eventsStreamErrMsg is sent when the Docker events stream drops
+type eventsStreamErrMsg struct {
+	err error
+}
+
+// containerUpdatedMsg carries the refreshed state of a single container.
+// A nil info means the container no longer exists.
+type containerUpdatedMsg struct {
+	id   string
+	seq  int // eventsSeq of the event that triggered the fetch
+	info *containerInfo
+	err  error
+}
+
 // shellReadyMsg is sent when shell exec session is ready
 type shellReadyMsg struct {
-	execID string
-	err    error
+	sessionID   int
+	containerID string
+	execID      string
+	conn        *types.HijackedResponse
+	err         error
 }
 
-// shellOutputMsg contains output from the shell
+// shellOutputMsg contains a chunk of raw TTY output from the shell
 type shellOutputMsg struct {
-	line string
+	execID string
+	data   []byte
 }
 
-// shellCommandResultMsg contains result of executing a shell command
-type shellCommandResultMsg struct {
-	command string
-	output  string
-	err     error
+// shellExitedMsg is sent when the shell's TTY stream closes
+type shellExitedMsg struct {
+	execID string
+	err    error
+}
+
+// shellHungUpMsg is sent once a detached shell has been sent SIGHUP
+type shellHungUpMsg struct {
+	err error
 }
 
 // ContainerPlatform represents a container runtime platform
@@ -345,46 +635,257 @@ func initialModel(ctx context.Context, cli *client.Client) Model {
 		currentView:  viewList,
 		hideK8s:      true,   // Hide k8s containers by default
 		hideExited:   true,   // Hide exited containers by default
+		logsTail:     defaultLogsTail,
+	}
+}
+
+// resyncInterval is how often a full container list is fetched while the
+// events stream is down
+const resyncInterval = 5 * time.Second
+
+// Labels Docker Compose sets on the containers it creates
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// shortID returns the 12-character short form of a container ID
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// newContainerInfo converts a Docker API container summary to display information
+func newContainerInfo(c container.Summary) containerInfo {
+	// Remove leading slash from container name
+	name := ""
+	if len(c.Names) > 0 {
+		name = strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	var volumes []string
+	for _, mount := range c.Mounts {
+		if mount.Type == "volume" {
+			volumes = append(volumes, mount.Name)
+		}
+	}
+
+	networks := make(map[string]string)
+	if c.NetworkSettings != nil {
+		for name, endpoint := range c.NetworkSettings.Networks {
+			ip := ""
+			if endpoint != nil {
+				ip = endpoint.IPAddress
+			}
+			networks[name] = ip
+		}
+	}
+
+	// Format ports
+	var ports []string
+	for _, port := range c.Ports {
+		if port.PublicPort > 0 {
+			// Port is mapped to host
+			ports = append(ports, fmt.Sprintf("%d:%d/%s", port.PublicPort, port.PrivatePort, port.Type))
+		} else {
+			// Port is exposed but not mapped
+			ports = append(ports, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
+		}
+	}
+
+	return containerInfo{
+		ID:       shortID(c.ID),
+		Name:     name,
+		Image:    c.Image,
+		Status:   c.Status,
+		State:    c.State,
+		Ports:    ports,
+		ImageID:  c.ImageID,
+		Volumes:  volumes,
+		Networks: networks,
+		Project:  c.Labels[composeProjectLabel],
+		Service:  c.Labels[composeServiceLabel],
 	}
 }
 
 // loadContainers fetches containers from Docker API
 func (m Model) loadContainers(showRefresh bool) tea.Cmd {
+	seq := m.eventsSeq
 	return func() tea.Msg {
 		containers, err := m.dockerClient.ContainerList(m.ctx, container.ListOptions{All: true})
 		if err != nil {
-			return containersLoadedMsg{err: err, showRefresh: showRefresh}
+			return containersLoadedMsg{err: err, showRefresh: showRefresh, seq: seq}
 		}
 
 		var containerList []containerInfo
 		for _, c := range containers {
-			// Remove leading slash from container name
-			name := strings.TrimPrefix(c.Names[0], "/")
+			containerList = append(containerList, newContainerInfo(c))
+		}
 
-		// Format ports
-		var ports []string
-		for _, port := range c.Ports {
-			if port.PublicPort > 0 {
-				// Port is mapped to host
-				ports = append(ports, fmt.Sprintf("%d:%d/%s", port.PublicPort, port.PrivatePort, port.Type))
-			} else {
-				// Port is exposed but not mapped
-				ports = append(ports, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
+		return containersLoadedMsg{containers: containerList, showRefresh: showRefresh, seq: seq}
+	}
+}
+
+// loadContainer fetches the current state of a single container from Docker
+// API. seq is the event that asked for it, so results that come back out of
+// order can be dropped.
+func (m Model) loadContainer(id string, seq int) tea.Cmd {
+	return func() tea.Msg {
+		containers, err := m.dockerClient.ContainerList(m.ctx, container.ListOptions{
+			All:     true,
+			Filters: filters.NewArgs(filters.Arg("id", id)),
+		})
+		if err != nil {
+			return containerUpdatedMsg{id: shortID(id), seq: seq, err: err}
+		}
+		if len(containers) == 0 {
+			return containerUpdatedMsg{id: shortID(id), seq: seq}
+		}
+
+		info := newContainerInfo(containers[0])
+		return containerUpdatedMsg{id: info.ID, seq: seq, info: &info}
+	}
+}
+
+// subscribeEvents opens a Docker events stream filtered to container events
+func (m Model) subscribeEvents() tea.Msg {
+	messages, errs := m.dockerClient.Events(m.ctx, events.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("type", string(events.ContainerEventType))),
+	})
+	return eventsSubscribedMsg{messages: messages, errs: errs}
+}
+
+// waitForEvent returns a command that blocks until the next event (or stream error) arrives
+func waitForEvent(messages <-chan events.Message, errs <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case event, ok := <-messages:
+			if !ok {
+				return eventsStreamErrMsg{err: io.EOF}
+			}
+			return containerEventMsg{event: event}
+		case err := <-errs:
+			if err == nil {
+				err = io.EOF
 			}
+			return eventsStreamErrMsg{err: err}
 		}
+	}
+}
 
-			containerList = append(containerList, containerInfo{
-				ID:     c.ID[:12], // Short ID
-				Name:   name,
-				Image:  c.Image,
-				Status: c.Status,
-				State:  c.State,
-			Ports:  ports,
-			})
+// applyContainerEvent updates allContainers from a single container event.
+//
+// Destroy and rename events are applied locally. Events that change a
+// container's state or status text trigger a fetch of that one container,
+// which is far cheaper than re-listing everything. Noisy events such as
+// exec_* and attach are ignored.
+func (m *Model) applyContainerEvent(event events.Message) tea.Cmd {
+	id := shortID(event.Actor.ID)
+
+	switch event.Action {
+	case events.ActionDestroy:
+		m.nextContainerSeq(id)
+		m.removeContainer(id)
+		return nil
+	case events.ActionRename:
+		seq := m.nextContainerSeq(id)
+		name := event.Actor.Attributes["name"]
+		if name == "" {
+			return m.loadContainer(event.Actor.ID, seq)
+		}
+		for i := range m.allContainers {
+			if m.allContainers[i].ID == id {
+				m.allContainers[i].Name = name
+			}
+		}
+		m.filterContainers()
+		return nil
+	case events.ActionCreate, events.ActionStart, events.ActionDie,
+		events.ActionPause, events.ActionUnPause, events.ActionUpdate:
+		return m.loadContainer(event.Actor.ID, m.nextContainerSeq(id))
+	}
+
+	if strings.HasPrefix(string(event.Action), string(events.ActionHealthStatus)) {
+		return m.loadContainer(event.Actor.ID, m.nextContainerSeq(id))
+	}
+	return nil
+}
+
+// nextContainerSeq records a new event for a container. Fetches started for
+// its earlier events are then dropped when they return, and full lists
+// requested before it don't overwrite it.
+func (m *Model) nextContainerSeq(id string) int {
+	m.eventsSeq++
+	if m.containerSeq == nil {
+		m.containerSeq = make(map[string]int)
+	}
+	m.containerSeq[id] = m.eventsSeq
+	return m.eventsSeq
+}
+
+// reconcileContainers applies a full list requested at eventsSeq seq.
+// Containers with a later event keep their current state (or stay removed),
+// since the list may predate it.
+func (m *Model) reconcileContainers(list []containerInfo, seq int) {
+	newer := func(id string) bool { return m.containerSeq[id] > seq }
+	current := make(map[string]containerInfo)
+	for _, c := range m.allContainers {
+		current[c.ID] = c
+	}
+
+	var added, merged []containerInfo
+	listed := make(map[string]bool)
+	for _, c := range list {
+		listed[c.ID] = true
+		if !newer(c.ID) {
+			merged = append(merged, c)
+		} else if cur, ok := current[c.ID]; ok {
+			merged = append(merged, cur)
+		}
+	}
+	for _, c := range m.allContainers {
+		if newer(c.ID) && !listed[c.ID] {
+			added = append(added, c)
+		}
+	}
+	m.allContainers = append(added, merged...)
+
+	// Events up to seq are covered by this list from now on
+	m.listSeq = seq
+	for id, s := range m.containerSeq {
+		if s <= seq {
+			delete(m.containerSeq, id)
+		}
+	}
+}
+
+// upsertContainer replaces a container in allContainers, or adds it if new.
+// New containers go to the front to match ContainerList's newest-first order.
+func (m *Model) upsertContainer(info containerInfo) {
+	for i := range m.allContainers {
+		if m.allContainers[i].ID == info.ID {
+			m.allContainers[i] = info
+			m.filterContainers()
+			return
 		}
+	}
+	m.allContainers = append([]containerInfo{info}, m.allContainers...)
+	m.filterContainers()
+}
 
-		return containersLoadedMsg{containers: containerList, showRefresh: showRefresh}
+// removeContainer drops a container from allContainers
+func (m *Model) removeContainer(id string) {
+	kept := m.allContainers[:0:0]
+	for _, c := range m.allContainers {
+		if c.ID != id {
+			kept = append(kept, c)
+		}
 	}
+	m.allContainers = kept
+	delete(m.selected, id)
+	m.filterContainers()
 }
 
 // clearStatusAfterDelay returns a command that sends clearStatusMsg after a delay
@@ -394,9 +895,9 @@ func clearStatusAfterDelay(delay time.Duration) tea.Cmd {
 	})
 }
 
-// tickCmd returns a command that sends tickMsg every 1 second for auto-refresh
+// tickCmd returns a command that sends tickMsg after resyncInterval
 func tickCmd() tea.Cmd {
-	return tea.Tick(1*time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(resyncInterval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -424,6 +925,11 @@ func (m *Model) filterContainers() {
 			continue
 		}
 
+		// Filter paused containers
+		if m.hidePaused && c.State == "paused" {
+			continue
+		}
+
 		filtered = append(filtered, c)
 	}
 
@@ -436,1171 +942,9115 @@ func (m *Model) filterContainers() {
 	if m.cursor < 0 && len(m.containers) > 0 {
 		m.cursor = 0
 	}
-}
-
-// startContainer starts the selected container
-func (m Model) startContainer() tea.Msg {
-	if len(m.containers) == 0 {
-		return operationCompleteMsg{false, "No container selected"}
-	}
+	m.sortContainers()
 
-	containerID := m.containers[m.cursor].ID
-	err := m.dockerClient.ContainerStart(m.ctx, containerID, container.StartOptions{})
-	if err != nil {
-		return operationCompleteMsg{false, fmt.Sprintf("Failed to start: %v", err)}
+	// Leave a project header that is no longer shown
+	if !m.groupByProject || len(m.projectContainers(m.cursorProject)) == 0 {
+		m.cursorProject = ""
 	}
-
-	return operationCompleteMsg{true, fmt.Sprintf("Started container %s", containerID)}
 }
 
-// stopContainer stops the selected container
-func (m Model) stopContainer() tea.Msg {
-	if len(m.containers) == 0 {
-		return operationCompleteMsg{false, "No container selected"}
+// toggleSelected adds or removes a container from the selection
+func (m *Model) toggleSelected(id string) {
+	if m.selected == nil {
+		m.selected = make(map[string]bool)
 	}
-
-	containerID := m.containers[m.cursor].ID
-	timeout := 10
-	err := m.dockerClient.ContainerStop(m.ctx, containerID, container.StopOptions{Timeout: &timeout})
-	if err != nil {
-		return operationCompleteMsg{false, fmt.Sprintf("Failed to stop: %v", err)}
+	if m.selected[id] {
+		delete(m.selected, id)
+	} else {
+		m.selected[id] = true
 	}
-
-	return operationCompleteMsg{true, fmt.Sprintf("Stopped container %s", containerID)}
 }
 
-// restartContainer restarts the selected container
-func (m Model) restartContainer() tea.Msg {
-	if len(m.containers) == 0 {
-		return operationCompleteMsg{false, "No container selected"}
+// matchesFilter reports whether a container's name, ID, image or compose
+// labels contain the query (case-insensitive)
+func (c containerInfo) matchesFilter(query string) bool {
+	query = strings.ToLower(query)
+	for _, field := range []string{c.Name, c.ID, c.Image, c.Project, c.Service} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
 	}
+	return false
+}
 
-	containerID := m.containers[m.cursor].ID
-	timeout := 10
-	err := m.dockerClient.ContainerRestart(m.ctx, containerID, container.StopOptions{Timeout: &timeout})
-	if err != nil {
-		return operationCompleteMsg{false, fmt.Sprintf("Failed to restart: %v", err)}
+// handleSelectPromptKey edits the select-by-filter prompt; enter adds every
+// shown container matching the filter to the selection
+func (m *Model) handleSelectPromptKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.selectPrompt = false
+	case "enter":
+		m.selectPrompt = false
+		if m.selectPromptInput == "" {
+			return nil
+		}
+		matched := 0
+		for _, c := range m.containers {
+			if c.matchesFilter(m.selectPromptInput) {
+				matched++
+				if !m.selected[c.ID] {
+					m.toggleSelected(c.ID)
+				}
+			}
+		}
+		m.statusMsg = fmt.Sprintf("Selected %d matching %q (%d selected)", matched, m.selectPromptInput, len(m.selectedContainers()))
+	case "backspace":
+		if len(m.selectPromptInput) > 0 {
+			m.selectPromptInput = m.selectPromptInput[:len(m.selectPromptInput)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.selectPromptInput += string(msg.Runes)
+		}
 	}
-
-	return operationCompleteMsg{true, fmt.Sprintf("Restarted container %s", containerID)}
+	return nil
 }
 
-// destroyContainer removes the selected container (must be stopped first).
-//
-// This operation permanently deletes the container and its associated data.
-// The container must be stopped before it can be removed. If the container is
-// running, this will fail with an appropriate error message.
-//
-// A confirmation dialog (via confirmingDestroy state) should be shown before
-// calling this function to prevent accidental deletion.
-func (m Model) destroyContainer() tea.Msg {
-	if len(m.containers) == 0 {
-		return operationCompleteMsg{false, "No container selected"}
+// selectedContainers returns the selected containers that are currently
+// shown, in list order
+func (m Model) selectedContainers() []containerInfo {
+	var selected []containerInfo
+	for _, c := range m.containers {
+		if m.selected[c.ID] {
+			selected = append(selected, c)
+		}
 	}
+	return selected
+}
 
-	containerID := m.containers[m.cursor].ID
-	err := m.dockerClient.ContainerRemove(m.ctx, containerID, container.RemoveOptions{})
-	if err != nil {
-		return operationCompleteMsg{false, fmt.Sprintf("Failed to destroy: %v", err)}
+// sortContainers orders the shown containers by compose project (when
+// grouping) and the selected stats column, keeping the cursor on the same
+// container
+func (m *Model) sortContainers() {
+	if len(m.containers) == 0 || (m.statsSort == statsSortNone && !m.groupByProject) {
+		return
 	}
-
-	return operationCompleteMsg{true, fmt.Sprintf("Destroyed container %s", containerID)}
-}
-// openBrowserForContainer opens a web browser for the first available HTTP port of the selected container
-func (m Model) openBrowserForContainer() tea.Cmd {
-	return func() tea.Msg {
-		if len(m.containers) == 0 {
-			return operationCompleteMsg{false, "No container selected"}
+	cursorID := m.containers[m.cursor].ID
+	if m.statsSort != statsSortNone {
+		sort.SliceStable(m.containers, func(i, j int) bool {
+			return m.statsSort.value(m.stats[m.containers[i].ID]) > m.statsSort.value(m.stats[m.containers[j].ID])
+		})
+	}
+	if m.groupByProject {
+		// Projects in name order, standalone containers last
+		sort.SliceStable(m.containers, func(i, j int) bool {
+			a, b := m.containers[i].Project, m.containers[j].Project
+			if a == "" || b == "" {
+				return b == "" && a != ""
+			}
+			return a < b
+		})
+	}
+	for i, c := range m.containers {
+		if c.ID == cursorID {
+			m.cursor = i
+			break
 		}
+	}
+}
 
-		container := m.containers[m.cursor]
+// listRow is one line of the container list: a compose project header or a container
+type listRow struct {
+	project   string // Project of a header row
+	container int    // Index into m.containers (-1 for header rows)
+}
 
-		// Find the first public port
-		var publicPort int
-		for _, portStr := range container.Ports {
-			// Parse port string like "8080:80/tcp" or "8080/tcp"
-			// First try format with colon (mapped port)
-			if strings.Contains(portStr, ":") {
-				parts := strings.Split(portStr, ":")
-				if len(parts) >= 2 {
-					// Extract the host port (first part)
-					portParts := strings.Split(parts[0], "/")
-					if n, err := fmt.Sscanf(portParts[0], "%d", &publicPort); n == 1 && err == nil && publicPort > 0 {
-						break
-					}
-				}
+// listRows returns the lines of the container list. When grouping, each
+// compose project gets a header and collapsed projects hide their containers.
+func (m Model) listRows() []listRow {
+	rows := make([]listRow, 0, len(m.containers))
+	for i, c := range m.containers {
+		if m.groupByProject && c.Project != "" {
+			if i == 0 || m.containers[i-1].Project != c.Project {
+				rows = append(rows, listRow{project: c.Project, container: -1})
 			}
-		}
-
-		if publicPort == 0 {
-			if len(container.Ports) == 0 {
-				return operationCompleteMsg{false, "Container has no exposed ports"}
+			if m.collapsed[c.Project] {
+				continue
 			}
-			return operationCompleteMsg{false, fmt.Sprintf("No mapped ports (have: %s)", strings.Join(container.Ports, ", "))}
 		}
+		rows = append(rows, listRow{container: i})
+	}
+	return rows
+}
 
-		url := fmt.Sprintf("http://localhost:%d", publicPort)
-
-		// Open browser based on OS
-		var cmd *exec.Cmd
-		switch runtime.GOOS {
-		case "darwin":
-			cmd = exec.Command("open", url)
-		case "linux":
-			cmd = exec.Command("xdg-open", url)
-		case "windows":
-			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-		default:
-			return operationCompleteMsg{false, "Unsupported operating system"}
+// cursorRow returns the index of the row under the cursor
+func (m Model) cursorRow(rows []listRow) int {
+	project := m.cursorProject
+	if project == "" && m.cursor < len(m.containers) && m.collapsed[m.containers[m.cursor].Project] {
+		// The cursor's container is hidden, so its header stands in for it
+		project = m.containers[m.cursor].Project
+	}
+	for i, row := range rows {
+		if project != "" && row.container < 0 && row.project == project {
+			return i
 		}
-
-		if err := cmd.Start(); err != nil {
-			return operationCompleteMsg{false, fmt.Sprintf("Failed to open browser: %v", err)}
+		if project == "" && row.container == m.cursor {
+			return i
 		}
-
-		return operationCompleteMsg{true, fmt.Sprintf("Opened %s in browser", url)}
 	}
+	return 0
 }
 
-
-// inspectContainer retrieves detailed information about the selected container
-func (m Model) inspectContainer() tea.Msg {
-	if len(m.containers) == 0 {
-		return inspectDataMsg{err: fmt.Errorf("no container selected")}
+// moveCursor moves the cursor by delta rows, landing on headers as well as containers
+func (m *Model) moveCursor(delta int) {
+	rows := m.listRows()
+	if len(rows) == 0 {
+		return
 	}
+	i := max(0, min(len(rows)-1, m.cursorRow(rows)+delta))
+	m.setCursorRow(rows[i])
+}
 
-	containerID := m.containers[m.cursor].ID
-	inspect, err := m.dockerClient.ContainerInspect(m.ctx, containerID)
-	if err != nil {
-		return inspectDataMsg{err: err}
+// setCursorRow puts the cursor on a row. On a header, m.cursor points at the
+// project's first container so m.containers[m.cursor] stays valid.
+func (m *Model) setCursorRow(row listRow) {
+	if row.container >= 0 {
+		m.cursor = row.container
+		m.cursorProject = ""
+		return
 	}
-
-	// Pretty print JSON
-	data, err := json.MarshalIndent(inspect, "", "  ")
-	if err != nil {
-		return inspectDataMsg{err: err}
+	m.cursorProject = row.project
+	for i, c := range m.containers {
+		if c.Project == row.project {
+			m.cursor = i
+			break
+		}
 	}
-
-	return inspectDataMsg{data: string(data)}
 }
 
-// viewContainerLogs retrieves logs from the selected container
-func (m Model) viewContainerLogs() tea.Msg {
-	if len(m.containers) == 0 {
-		return logsDataMsg{err: fmt.Errorf("no container selected")}
+// projectContainers returns the shown containers of a compose project,
+// including those hidden by collapsing
+func (m Model) projectContainers(project string) []containerInfo {
+	var containers []containerInfo
+	for _, c := range m.containers {
+		if c.Project == project {
+			containers = append(containers, c)
+		}
 	}
+	return containers
+}
 
-	containerID := m.containers[m.cursor].ID
-	options := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Tail:       "100",
+// toggleCollapsed collapses or expands a compose project
+func (m *Model) toggleCollapsed(project string) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
 	}
-
-	logs, err := m.dockerClient.ContainerLogs(m.ctx, containerID, options)
-	if err != nil {
-		return logsDataMsg{err: err}
+	m.collapsed[project] = !m.collapsed[project]
+	if m.collapsed[project] {
+		m.setCursorRow(listRow{project: project, container: -1})
 	}
-	defer logs.Close()
+}
 
-	// Read logs
-	data, err := io.ReadAll(logs)
-	if err != nil {
-		return logsDataMsg{err: err}
+// actionTargets returns the containers an action applies to: the selection
+// if there is one, otherwise the cursor's targets
+func (m Model) actionTargets() []containerInfo {
+	if selected := m.selectedContainers(); len(selected) > 0 {
+		return selected
 	}
-
-	return logsDataMsg{data: string(data)}
+	return m.cursorTargets()
 }
 
-// shellIntoContainer opens an interactive shell popup for the selected container
-func (m *Model) shellIntoContainer() tea.Cmd {
+// cursorTargets returns the whole project when the cursor is on a project
+// header, otherwise the container under the cursor
+func (m Model) cursorTargets() []containerInfo {
 	if len(m.containers) == 0 {
 		return nil
 	}
-
-	containerID := m.containers[m.cursor].ID
-	containerName := m.containers[m.cursor].Name
-
-	// Initialize shell state
-	m.shellContainerID = containerID
-	m.shellContainerName = containerName
-	m.shellOutput = []string{
-		fmt.Sprintf("Shell session for container: %s", containerName),
-		fmt.Sprintf("Container ID: %s", containerID),
-		"",
-		"Type commands and press ENTER to execute.",
-		"",
+	if m.cursorProject != "" {
+		return m.projectContainers(m.cursorProject)
 	}
-	m.shellInput = ""
-	m.shellScroll = 0
+	return []containerInfo{m.containers[m.cursor]}
+}
 
-	// Switch to shell view
-	m.currentView = viewShell
+// bulkOperation tracks an operation running across several containers
+type bulkOperation struct {
+	id       int
+	progress string           // Shown while running, e.g. "Stopping"
+	past     string           // Shown when done, e.g. "Stopped"
+	targets  []containerInfo  // Containers in list order
+	results  map[string]error // Outcome by container ID, once finished
+}
 
-	return nil
+// bulkResultMsg reports one container's outcome in a bulk operation
+type bulkResultMsg struct {
+	opID int
+	id   string
+	err  error
 }
 
-// executeShellCommand executes a command in the container and returns the output
-func (m Model) executeShellCommand(command string) tea.Cmd {
-	return func() tea.Msg {
-		// Create exec configuration for the command
-		execConfig := container.ExecOptions{
-			AttachStdout: true,
-			AttachStderr: true,
-			Cmd:          []string{"/bin/sh", "-c", command},
-		}
-
-		// Create exec instance
-		execResp, err := m.dockerClient.ContainerExecCreate(m.ctx, m.shellContainerID, execConfig)
-		if err != nil {
-			return shellCommandResultMsg{
-				command: command,
-				output:  "",
-				err:     fmt.Errorf("failed to create exec: %w", err),
-			}
-		}
-
-		// Attach to exec instance
-		attachResp, err := m.dockerClient.ContainerExecAttach(m.ctx, execResp.ID, container.ExecStartOptions{})
-		if err != nil {
-			return shellCommandResultMsg{
-				command: command,
-				output:  "",
-				err:     fmt.Errorf("failed to attach: %w", err),
-			}
-		}
-		defer attachResp.Close()
+// done reports whether every container has finished
+func (b *bulkOperation) done() bool {
+	return len(b.results) == len(b.targets)
+}
 
-		// Read all output
-		output, err := io.ReadAll(attachResp.Reader)
-		if err != nil {
-			return shellCommandResultMsg{
-				command: command,
-				output:  "",
-				err:     fmt.Errorf("failed to read output: %w", err),
+// summary returns the per-container progress line shown in the status bar
+func (b *bulkOperation) summary() string {
+	var parts []string
+	succeeded := 0
+	var firstErr string
+	for _, c := range b.targets {
+		err, finished := b.results[c.ID]
+		switch {
+		case !finished:
+			parts = append(parts, c.Name+" …")
+		case err != nil:
+			parts = append(parts, c.Name+" ✗")
+			if firstErr == "" {
+				firstErr = fmt.Sprintf(" (%s: %v)", c.Name, err)
 			}
+		default:
+			parts = append(parts, c.Name+" ✓")
+			succeeded++
 		}
+	}
 
-		return shellCommandResultMsg{
-			command: command,
-			output:  string(output),
-			err:     nil,
-		}
+	if !b.done() {
+		return fmt.Sprintf("%s %d/%d: %s", b.progress, len(b.results), len(b.targets), strings.Join(parts, "  "))
 	}
+	return fmt.Sprintf("%s %d/%d: %s%s", b.past, succeeded, len(b.targets), strings.Join(parts, "  "), firstErr)
 }
 
-// updateSearchResults updates the search results based on current input
-func (m *Model) updateSearchResults() {
-	m.searchResults = []searchResult{}
-	m.searchCursor = 0
-
-	query := strings.ToLower(m.searchInput)
-
-	// Search through containers
-	for _, c := range m.containers {
-		// Check if container matches the query (search name, ID, image, and ports)
-		nameLower := strings.ToLower(c.Name)
-		imageLower := strings.ToLower(c.Image)
-		idLower := strings.ToLower(c.ID)
-		portsLower := strings.ToLower(strings.Join(c.Ports, " "))
+// containerOp applies op to the given containers. A single container reports
+// through operationCompleteMsg; several run concurrently and report each
+// result as it finishes. verb, progress and past describe the operation
+// ("start", "Starting", "Started").
+func (m *Model) containerOp(targets []containerInfo, verb, progress, past string, op func(id string) error) tea.Cmd {
+	if len(targets) == 0 {
+		return func() tea.Msg {
+			return operationCompleteMsg{false, "No container selected"}
+		}
+	}
 
-		if query == "" || strings.Contains(nameLower, query) ||
-			strings.Contains(imageLower, query) || strings.Contains(idLower, query) ||
-			strings.Contains(portsLower, query) {
-			// Build description with ports if available
-			portsStr := strings.Join(c.Ports, ", ")
-			if portsStr == "" {
-				portsStr = "no ports"
+	if len(targets) == 1 {
+		id := targets[0].ID
+		return func() tea.Msg {
+			if err := op(id); err != nil {
+				return operationCompleteMsg{false, fmt.Sprintf("Failed to %s: %v", verb, err)}
 			}
-			m.searchResults = append(m.searchResults, searchResult{
-				resultType:  "container",
-				display:     c.Name,
-				description: fmt.Sprintf("%s | %s | %s | %s", c.ID, c.Image, portsStr, c.State),
-				containerID: c.ID,
-			})
+			return operationCompleteMsg{true, fmt.Sprintf("%s container %s", past, id)}
 		}
 	}
 
-	// Add available commands that match the query
-	commands := []struct {
-		key         string
-		name        string
-		description string
-	}{
-		{"s", "Start", "Start the selected container"},
-		{"t", "Stop", "Stop the selected container"},
-		{"R", "Restart", "Restart the selected container"},
-		{"d", "Destroy", "Permanently remove the selected container"},
-		{"i", "Inspect", "View container details"},
-		{"l", "Logs", "View container logs"},
-		{"e", "Shell", "Open shell in container"},
-		{"o", "Browser", "Open container port in browser"},
-		{"h", "Toggle K8s", "Show/hide Kubernetes containers"},
-		{"a", "Toggle Exited", "Show/hide exited containers"},
-		{"r", "Refresh", "Refresh container list"},
+	m.bulkOpID++
+	m.bulkOp = &bulkOperation{
+		id:       m.bulkOpID,
+		progress: progress,
+		past:     past,
+		targets:  targets,
+		results:  make(map[string]error),
 	}
+	m.statusMsg = m.bulkOp.summary()
+
+	// tea.Batch runs the commands concurrently
+	opID := m.bulkOpID
+	var cmds []tea.Cmd
+	for _, c := range targets {
+		id := c.ID
+		cmds = append(cmds, func() tea.Msg {
+			return bulkResultMsg{opID: opID, id: id, err: op(id)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
 
-	for _, cmd := range commands {
-		cmdLower := strings.ToLower(cmd.name)
-		descLower := strings.ToLower(cmd.description)
+// startContainer starts the selected containers
+func (m *Model) startContainer() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return m.containerOp(m.actionTargets(), "start", "Starting", "Started", func(id string) error {
+		return cli.ContainerStart(ctx, id, container.StartOptions{})
+	})
+}
 
-		if query == "" || strings.Contains(cmdLower, query) || strings.Contains(descLower, query) {
-			m.searchResults = append(m.searchResults, searchResult{
-				resultType:  "command",
-				display:     fmt.Sprintf("[%s] %s", cmd.key, cmd.name),
-				description: cmd.description,
-				command:     cmd.key,
-			})
-		}
-	}
+// stopContainer stops the selected containers
+func (m *Model) stopContainer() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	timeout := 10
+	return m.containerOp(m.actionTargets(), "stop", "Stopping", "Stopped", func(id string) error {
+		return cli.ContainerStop(ctx, id, container.StopOptions{Timeout: &timeout})
+	})
 }
 
-// executeSearchCommand executes a command from the search results
-func (m *Model) executeSearchCommand(command string) tea.Cmd {
-	switch command {
-	case "s":
-		m.statusMsg = "Starting container..."
-		return m.startContainer
-	case "t":
-		m.statusMsg = "Stopping container..."
-		return m.stopContainer
-	case "R":
-		m.statusMsg = "Restarting container..."
-		return m.restartContainer
-	case "d":
-		// Destroy container - show confirmation dialog
-		if len(m.containers) > 0 {
-			containerName := m.containers[m.cursor].Name
-			containerID := m.containers[m.cursor].ID
-			m.confirmingDestroy = true
-			m.containerToDestroy = containerID
-			m.statusMsg = fmt.Sprintf("⚠️  Destroy container '%s'? [y/n]", containerName)
-		}
-		return nil
-	case "i":
-		m.statusMsg = "Loading inspection data..."
-		return m.inspectContainer
-	case "l":
-		m.statusMsg = "Loading logs..."
-		return m.viewContainerLogs
-	case "e":
-		if len(m.containers) > 0 {
-			containerName := m.containers[m.cursor].Name
-			m.statusMsg = fmt.Sprintf("Opening shell in %s...", containerName)
-			return m.shellIntoContainer()
-		}
-	case "o":
-		if len(m.containers) > 0 {
-			m.statusMsg = "Opening browser..."
-			return m.openBrowserForContainer()
-		}
+// restartContainer restarts the selected containers
+func (m *Model) restartContainer() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	timeout := 10
+	return m.containerOp(m.actionTargets(), "restart", "Restarting", "Restarted", func(id string) error {
+		return cli.ContainerRestart(ctx, id, container.StopOptions{Timeout: &timeout})
+	})
+}
+
+// toggleFilter flips the list filter bound to key (h, a or z), reapplies
+// the filters and restarts the stats streams for the rows now visible
+func (m *Model) toggleFilter(key string) tea.Cmd {
+	switch key {
 	case "h":
 		m.hideK8s = !m.hideK8s
-		m.filterContainers()
 		if m.hideK8s {
 			m.statusMsg = "Hiding Kubernetes containers"
 		} else {
 			m.statusMsg = "Showing Kubernetes containers"
 		}
-		return clearStatusAfterDelay(3 * time.Second)
 	case "a":
 		m.hideExited = !m.hideExited
-		m.filterContainers()
 		if m.hideExited {
 			m.statusMsg = "Hiding exited containers"
 		} else {
 			m.statusMsg = "Showing all containers (including exited)"
 		}
+	case "z":
+		m.hidePaused = !m.hidePaused
+		if m.hidePaused {
+			m.statusMsg = "Hiding paused containers"
+		} else {
+			m.statusMsg = "Showing paused containers"
+		}
+	default:
+		return nil
+	}
+	m.filterContainers()
+	// Clear status after 3 seconds
+	return tea.Batch(m.syncStatsStreams(), clearStatusAfterDelay(3*time.Second))
+}
+
+// togglePause unpauses the paused containers of the selection when none
+// are running, and pauses the running ones otherwise
+func (m *Model) togglePause() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	targets := m.actionTargets()
+	var running, paused []containerInfo
+	for _, c := range targets {
+		switch c.State {
+		case "running":
+			running = append(running, c)
+		case "paused":
+			paused = append(paused, c)
+		}
+	}
+	if len(targets) > 0 && len(running) == 0 && len(paused) == 0 {
+		// Say why nothing can be paused rather than "No container selected"
+		switch {
+		case len(m.selectedContainers()) > 0:
+			m.statusMsg = "No running or paused containers in selection"
+		case m.cursorProject != "":
+			m.statusMsg = "No running or paused containers in project " + m.cursorProject
+		default:
+			m.statusMsg = fmt.Sprintf("Cannot pause %s: container is %s", targets[0].Name, targets[0].State)
+		}
 		return clearStatusAfterDelay(3 * time.Second)
-	case "r":
-		m.loading = true
-		m.statusMsg = ""
-		return m.loadContainers(true)
+	}
+	if len(running) == 0 && len(paused) > 0 {
+		m.statusMsg = "Unpausing container..."
+		return m.containerOp(paused, "unpause", "Unpausing", "Unpaused", func(id string) error {
+			return cli.ContainerUnpause(ctx, id)
+		})
+	}
+	m.statusMsg = "Pausing container..."
+	return m.containerOp(running, "pause", "Pausing", "Paused", func(id string) error {
+		return cli.ContainerPause(ctx, id)
+	})
+}
+
+// killSignals are offered by the kill signal picker, SIGKILL first as it
+// is what `docker kill` sends by default
+var killSignals = []string{"SIGKILL", "SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGUSR1", "SIGUSR2"}
+
+// openKillPicker asks which signal to send to the selected containers
+func (m *Model) openKillPicker() {
+	m.killTargets = m.actionTargets()
+	if len(m.killTargets) == 0 {
+		m.statusMsg = "No container selected"
+		return
+	}
+	m.killPicker = true
+	m.killSignal = 0
+}
+
+// handleKillPickerKey chooses a signal and sends it to the kill targets
+func (m *Model) handleKillPickerKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "q":
+		m.killPicker = false
+		m.killTargets = nil
+	case "left", "h":
+		m.killSignal = (m.killSignal + len(killSignals) - 1) % len(killSignals)
+	case "right", "l", "tab":
+		m.killSignal = (m.killSignal + 1) % len(killSignals)
+	case "enter":
+		m.killPicker = false
+		cli, ctx := m.dockerClient, m.ctx
+		signal := killSignals[m.killSignal]
+		targets := m.killTargets
+		m.killTargets = nil
+		progress, past := "Sending "+signal+" to", "Sent "+signal+" to"
+		if signal == "SIGKILL" {
+			progress, past = "Killing", "Killed"
+		}
+		m.statusMsg = progress + " container..."
+		return m.containerOp(targets, "kill", progress, past, func(id string) error {
+			return cli.ContainerKill(ctx, id, signal)
+		})
+	default:
+		// Digits pick a signal directly
+		if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(killSignals) {
+			m.killSignal = n - 1
+		}
 	}
 	return nil
 }
 
-// enablePasteCmd returns a command to enable bracketed paste support.
-//
-// Bracketed paste mode allows the application to distinguish between typed text
-// and pasted text. When enabled, pasted content is received as KeyMsg events with
-// the Paste field set to true, and the pasted text available in the Runes field.
+// signalChoices renders the signal picker choices with the selected one highlighted
+func signalChoices(selected int) string {
+	var choices []string
+	for i, signal := range killSignals {
+		choice := fmt.Sprintf("%d:%s", i+1, signal)
+		if i == selected {
+			choice = selectedStyle.Render(" " + choice + " ")
+		}
+		choices = append(choices, choice)
+	}
+	return strings.Join(choices, " ")
+}
+
+// confirmDestroy asks for confirmation before destroying the selected
+// containers, listing every name when there are several
+func (m *Model) confirmDestroy() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	m.confirmingDestroy = true
+	m.destroyTargets = targets
+	if len(m.selectedContainers()) == 0 && m.cursorProject != "" {
+		m.statusMsg = fmt.Sprintf("⚠️  Destroy all %d containers in project '%s'? [y/n]", len(targets), m.cursorProject)
+		return
+	}
+	if len(targets) == 1 {
+		m.containerToDestroy = targets[0].ID
+		m.statusMsg = fmt.Sprintf("⚠️  Destroy container '%s'? [y/n]", targets[0].Name)
+		return
+	}
+
+	var names []string
+	for _, c := range targets {
+		names = append(names, c.Name)
+	}
+	m.statusMsg = fmt.Sprintf("⚠️  Destroy %d containers (%s)? [y/n]", len(targets), strings.Join(names, ", "))
+}
+
+// destroyContainer removes the containers confirmed for destruction (they
+// must be stopped first).
 //
-// This is automatically disabled when the program exits.
+// This operation permanently deletes the container and its associated data.
+// The container must be stopped before it can be removed. If the container is
+// running, this will fail with an appropriate error message.
 //
-// Integration points:
-//   - Called in Init() to enable paste support at program startup
-//   - Paste events are handled in Update() via KeyMsg with Paste=true
-//   - Supported in shell view (appends to command input) and search view (appends to search query)
-func enablePasteCmd() tea.Msg {
-	return tea.EnableBracketedPaste()
+// A confirmation dialog (via confirmDestroy) should be shown before calling
+// this function to prevent accidental deletion.
+func (m *Model) destroyContainer() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	targets := m.destroyTargets
+	m.destroyTargets = nil
+	return m.containerOp(targets, "destroy", "Destroying", "Destroyed", func(id string) error {
+		return cli.ContainerRemove(ctx, id, container.RemoveOptions{})
+	})
 }
 
-// Init is called when the program starts
-func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.loadContainers(false), // Don't show refresh message on initial load
-		tickCmd(),               // Start auto-refresh ticker
-		enablePasteCmd,          // Enable clipboard paste support
-	)
-}
+// openBrowserForContainer opens a web browser for the first available HTTP port of the selected container
+func (m Model) openBrowserForContainer() tea.Cmd {
+	return func() tea.Msg {
+		if len(m.containers) == 0 {
+			return operationCompleteMsg{false, "No container selected"}
+		}
 
-// Update handles messages and updates the model
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		return m, nil
-	case tea.KeyMsg:
-		// Handle clipboard paste events (bracketed paste mode)
-		//
-		// Bracketed paste is enabled via tea.EnableBracketedPaste() in Init().
-		// When users paste content (Cmd+V/Ctrl+V), the terminal sends the entire
-		// pasted text as a single KeyMsg with:
-		//   - Paste field = true (indicates this is a paste operation)
-		//   - Runes field = the complete pasted text as []rune
-		//
-		// This allows us to distinguish between typed input and pasted content,
-		// and handle multi-line or large pastes efficiently in one operation.
-		//
-		// Paste handling by view:
-		//   - viewShell: Appends to shell command input (useful for pasting commands)
-		//   - viewSearch: Appends to search query and updates results (useful for searching container names/IDs)
-		//   - Other views: Paste events are ignored
-		//
-		// Note: To copy text FROM the terminal, users should hold Shift while
-		// selecting text with the mouse, then use Cmd+C (Mac) or Ctrl+Shift+C (Linux).
-		if msg.Paste {
-			pastedText := string(msg.Runes)
-			switch m.currentView {
-			case viewShell:
-				// In shell view, append pasted text to shell input
-				m.shellInput += pastedText
-				return m, nil
-			case viewSearch:
-				// In search view, append pasted text to search input and update results
-				m.searchInput += pastedText
-				m.updateSearchResults()
-				return m, nil
+		container := m.containers[m.cursor]
+
+		// Find the first public port
+		var publicPort int
+		for _, portStr := range container.Ports {
+			// Parse port string like "8080:80/tcp" or "8080/tcp"
+			// First try format with colon (mapped port)
+			if strings.Contains(portStr, ":") {
+				parts := strings.Split(portStr, ":")
+				if len(parts) >= 2 {
+					// Extract the host port (first part)
+					portParts := strings.Split(parts[0], "/")
+					if n, err := fmt.Sscanf(portParts[0], "%d", &publicPort); n == 1 && err == nil && publicPort > 0 {
+						break
+					}
+				}
 			}
-			// For other views, ignore paste events
-			return m, nil
 		}
-		// Handle different views
-		switch m.currentView {
-		case viewInspect, viewLogs:
-			// In inspect or logs view, only allow escape to go back
-			switch msg.String() {
-			case "esc", "q":
-				m.currentView = viewList
-				m.inspectData = ""
-				m.logsData = ""
+
+		if publicPort == 0 {
+			if len(container.Ports) == 0 {
+				return operationCompleteMsg{false, "Container has no exposed ports"}
 			}
-		case viewShell:
-			// In shell view, handle shell input
-			switch msg.String() {
-			case "esc":
-				// Exit shell view
-				m.currentView = viewList
-				m.shellOutput = nil
-				m.shellInput = ""
-				m.shellExecID = ""
-			case "enter":
-				// Send command to shell
-				if m.shellInput != "" {
-					// Show command in output
-					m.shellOutput = append(m.shellOutput, "$ "+m.shellInput)
-					// Execute command and get result
-					cmd := m.shellInput
-					m.shellInput = ""
-					return m, m.executeShellCommand(cmd)
-				}
-			case "backspace":
-				// Delete last character
-				if len(m.shellInput) > 0 {
-					m.shellInput = m.shellInput[:len(m.shellInput)-1]
-				}
-			default:
-				// Add character to input
-				if len(msg.String()) == 1 {
-					m.shellInput += msg.String()
-				}
+			return operationCompleteMsg{false, fmt.Sprintf("No mapped ports (have: %s)", strings.Join(container.Ports, ", "))}
+		}
+
+		url := fmt.Sprintf("http://localhost:%d", publicPort)
+
+		// Open browser based on OS
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "linux":
+			cmd = exec.Command("xdg-open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			return operationCompleteMsg{false, "Unsupported operating system"}
+		}
+
+		if err := cmd.Start(); err != nil {
+			return operationCompleteMsg{false, fmt.Sprintf("Failed to open browser: %v", err)}
+		}
+
+		return operationCompleteMsg{true, fmt.Sprintf("Opened %s in browser", url)}
+	}
+}
+
+
+// inspectContainer retrieves detailed information about the selected container
+func (m Model) inspectContainer() tea.Msg {
+	if len(m.containers) == 0 {
+		return inspectDataMsg{err: fmt.Errorf("no container selected")}
+	}
+
+	containerID := m.containers[m.cursor].ID
+	inspect, err := m.dockerClient.ContainerInspect(m.ctx, containerID)
+	if err != nil {
+		return inspectDataMsg{err: err}
+	}
+
+	// Pretty print JSON
+	data, err := json.MarshalIndent(inspect, "", "  ")
+	if err != nil {
+		return inspectDataMsg{err: err}
+	}
+
+	return inspectDataMsg{data: string(data), info: &inspect}
+}
+
+// inspectTabs are the tabs of the container inspect view; image inspection
+// only has the raw JSON tab
+var inspectTabs = []string{"Overview", "Env", "Mounts", "Network", "Ports", "Labels", "Health", "Raw JSON"}
+
+// queryStep is one step of an inspect query: a field, an array index, or
+// every element
+type queryStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseQuery parses a JSONPath-style query such as $.State.Health.Status,
+// Mounts[0].Source, NetworkSettings.Networks.*.IPAddress or Config.Labels["com.example.key"]
+func parseQuery(query string) ([]queryStep, error) {
+	q := strings.TrimPrefix(strings.TrimSpace(query), "$")
+	var steps []queryStep
+	for i := 0; i < len(q); {
+		switch q[i] {
+		case '.':
+			i++
+			if i < len(q) && q[i] == '*' {
+				steps = append(steps, queryStep{wildcard: true})
+				i++
+				continue
 			}
-		case viewSearch:
-			// In search view, handle search input
-			switch msg.String() {
-			case "esc":
-				// Exit search view
-				m.currentView = viewList
-				m.searchInput = ""
-				m.searchResults = nil
-				m.searchCursor = 0
-			case "enter":
-				// Select the current result
-				if len(m.searchResults) > 0 && m.searchCursor < len(m.searchResults) {
-					result := m.searchResults[m.searchCursor]
-					m.currentView = viewList
-					m.searchInput = ""
-					m.searchResults = nil
+			fallthrough
+		default:
+			end := i
+			for end < len(q) && q[end] != '.' && q[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("expected a field name at position %d", i+1)
+			}
+			steps = append(steps, queryStep{field: q[i:end]})
+			i = end
+		case '[':
+			end := strings.IndexByte(q[i:], ']')
+			if end < 0 {
+				return nil, errors.New("missing ]")
+			}
+			inner := strings.TrimSpace(q[i+1 : i+end])
+			i += end + 1
+			if inner == "*" {
+				steps = append(steps, queryStep{wildcard: true})
+			} else if unquoted, err := strconv.Unquote(strings.ReplaceAll(inner, "'", `"`)); err == nil {
+				steps = append(steps, queryStep{field: unquoted})
+			} else if n, err := strconv.Atoi(inner); err == nil {
+				steps = append(steps, queryStep{index: n, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("invalid subscript [%s]", inner)
+			}
+		}
+	}
+	return steps, nil
+}
 
-					if result.resultType == "container" {
-						// Find and select the container
-						for i, c := range m.containers {
-							if c.ID == result.containerID {
-								m.cursor = i
-								break
-							}
+// evalQuery applies query steps to decoded JSON and returns every match.
+// Field names fall back to a case-insensitive match, and negative indexes
+// count from the end.
+func evalQuery(value any, steps []queryStep) []any {
+	current := []any{value}
+	for _, step := range steps {
+		var next []any
+		for _, v := range current {
+			switch v := v.(type) {
+			case map[string]any:
+				switch {
+				case step.wildcard:
+					for _, key := range slices.Sorted(maps.Keys(v)) {
+						next = append(next, v[key])
+					}
+				case step.isIndex:
+				default:
+					if field, ok := v[step.field]; ok {
+						next = append(next, field)
+						continue
+					}
+					for key, field := range v {
+						if strings.EqualFold(key, step.field) {
+							next = append(next, field)
+							break
 						}
-					} else if result.resultType == "command" {
-						// Execute the command
-						return m, m.executeSearchCommand(result.command)
 					}
 				}
-			case "up":
-				if m.searchCursor > 0 {
-					m.searchCursor--
-				}
-			case "down":
-				if m.searchCursor < len(m.searchResults)-1 {
-					m.searchCursor++
-				}
-			case "backspace":
-				if len(m.searchInput) > 0 {
-					m.searchInput = m.searchInput[:len(m.searchInput)-1]
-					m.updateSearchResults()
-				}
-			default:
-				// Add character to search input
-				if len(msg.String()) == 1 {
-					m.searchInput += msg.String()
-					m.updateSearchResults()
+			case []any:
+				switch {
+				case step.wildcard:
+					next = append(next, v...)
+				case step.isIndex:
+					i := step.index
+					if i < 0 {
+						i += len(v)
+					}
+					if i >= 0 && i < len(v) {
+						next = append(next, v[i])
+					}
 				}
 			}
-		case viewList:
-			// Handle confirmation dialog for destroy operation
-			if m.confirmingDestroy {
-				switch msg.String() {
-				case "y", "Y":
-					// User confirmed - destroy the container
-					m.confirmingDestroy = false
-					m.statusMsg = "Destroying container..."
-					return m, m.destroyContainer
-				case "n", "N", "esc":
-					// User cancelled - clear confirmation state
-					m.confirmingDestroy = false
-					m.containerToDestroy = ""
-					m.statusMsg = "Destroy cancelled"
-					return m, clearStatusAfterDelay(2 * time.Second)
-				}
-				// Ignore other keys during confirmation
-				return m, nil
+		}
+		current = next
+	}
+	return current
+}
+
+// runInspectQuery evaluates the raw tab query, leaving the result lines in
+// inspectQueryLines
+func (m *Model) runInspectQuery(query string) {
+	m.inspectQuery = strings.TrimSpace(query)
+	m.inspectQueryLines = nil
+	m.inspectOffset = 0
+	if m.inspectQuery == "" || m.inspectQuery == "$" {
+		m.inspectQuery = ""
+		return
+	}
+
+	steps, err := parseQuery(m.inspectQuery)
+	if err != nil {
+		m.inspectQueryLines = []string{"Invalid query: " + err.Error()}
+		return
+	}
+	var data any
+	if err := json.Unmarshal([]byte(m.inspectData), &data); err != nil {
+		m.inspectQueryLines = []string{"Error: " + err.Error()}
+		return
+	}
+	results := evalQuery(data, steps)
+	if len(results) == 0 {
+		m.inspectQueryLines = []string{"No match"}
+		return
+	}
+	var out any = results
+	if len(results) == 1 {
+		out = results[0]
+	}
+	formatted, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		m.inspectQueryLines = []string{"Error: " + err.Error()}
+		return
+	}
+	m.inspectQueryLines = strings.Split(string(formatted), "\n")
+}
+
+// inspectTabNames returns the tabs available for what is being inspected
+func (m Model) inspectTabNames() []string {
+	if m.inspectInfo == nil {
+		return inspectTabs[len(inspectTabs)-1:]
+	}
+	return inspectTabs
+}
+
+// tableLines lays out rows under a header, padding every column but the
+// last and cutting lines at width
+func tableLines(width int, headers []string, rows [][]string) []string {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths)-1; i++ {
+			widths[i] = max(widths[i], len(row[i]))
+		}
+	}
+	format := func(fields []string) string {
+		var b strings.Builder
+		for i, f := range fields {
+			if i < len(fields)-1 {
+				fmt.Fprintf(&b, "%-*s  ", widths[i], f)
+			} else {
+				b.WriteString(f)
 			}
+		}
+		line := b.String()
+		if width > 0 && len(line) > width {
+			line = line[:width]
+		}
+		return line
+	}
 
-			// In list view, handle all navigation and actions
-			switch msg.String() {
-			case "ctrl+c", "q":
-				return m, tea.Quit
-			case "up", "k":
-				if m.cursor > 0 {
-					m.cursor--
-				}
-			case "down", "j":
-				if m.cursor < len(m.containers)-1 {
-					m.cursor++
-				}
-			case "r", "f5":
-				// Refresh containers
-				m.loading = true
-				m.statusMsg = ""
-				return m, m.loadContainers(true) // Show refresh message
-			case "s":
-				// Start container
-				m.statusMsg = "Starting container..."
-				return m, m.startContainer
-			case "t":
-				// Stop container
-				m.statusMsg = "Stopping container..."
-				return m, m.stopContainer
-			case "R":
-				// Restart container (capital R)
-				m.statusMsg = "Restarting container..."
-				return m, m.restartContainer
-			case "i":
-				// Inspect container
-				m.statusMsg = "Loading inspection data..."
-				return m, m.inspectContainer
-			case "l":
-				// View logs
-				m.statusMsg = "Loading logs..."
-				return m, m.viewContainerLogs
-			case "e", "x":
-				// Shell into container
-				if len(m.containers) > 0 {
-					containerName := m.containers[m.cursor].Name
-					m.statusMsg = fmt.Sprintf("Opening shell in %s...", containerName)
-					return m, m.shellIntoContainer()
-				}
-			case "o":
-				// Open browser for container port
-				if len(m.containers) > 0 {
-					m.statusMsg = "Opening browser..."
-					return m, m.openBrowserForContainer()
-				}
-			case "d":
-				// Destroy container - show confirmation dialog
-				if len(m.containers) > 0 {
-					containerName := m.containers[m.cursor].Name
-					containerID := m.containers[m.cursor].ID
-					m.confirmingDestroy = true
-					m.containerToDestroy = containerID
-					m.statusMsg = fmt.Sprintf("⚠️  Destroy container '%s'? [y/n]", containerName)
-				}
-			case "h":
-				// Toggle hiding k8s containers
-				m.hideK8s = !m.hideK8s
-				m.filterContainers()
-				if m.hideK8s {
-					m.statusMsg = "Hiding Kubernetes containers"
-				} else {
-					m.statusMsg = "Showing Kubernetes containers"
-				}
-				// Clear status after 3 seconds
-				return m, clearStatusAfterDelay(3 * time.Second)
-			case "a":
-				// Toggle showing all containers (including exited)
-				m.hideExited = !m.hideExited
-				m.filterContainers()
-				if m.hideExited {
-					m.statusMsg = "Hiding exited containers"
-				} else {
-					m.statusMsg = "Showing all containers (including exited)"
+	lines := []string{keyStyle.Render(format(headers))}
+	for _, row := range rows {
+		lines = append(lines, format(row))
+	}
+	return lines
+}
+
+// fieldLines lays out "name  value" pairs, skipping empty values
+func fieldLines(fields [][2]string) []string {
+	width := 0
+	for _, f := range fields {
+		if f[1] != "" {
+			width = max(width, len(f[0]))
+		}
+	}
+	var lines []string
+	for _, f := range fields {
+		if f[1] != "" {
+			lines = append(lines, keyStyle.Render(fmt.Sprintf("%-*s", width+1, f[0]+":"))+" "+f[1])
+		}
+	}
+	return lines
+}
+
+// sortedPairs returns the entries of a string map as sorted [key, value] rows
+func sortedPairs(values map[string]string) [][]string {
+	var rows [][]string
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		rows = append(rows, []string{key, values[key]})
+	}
+	return rows
+}
+
+// inspectTabLines renders the current inspect tab as lines for scrolling
+func (m Model) inspectTabLines() []string {
+	tabs := m.inspectTabNames()
+	tab := tabs[min(m.inspectTab, len(tabs)-1)]
+	if tab == "Raw JSON" {
+		if m.inspectQuery != "" {
+			return m.inspectQueryLines
+		}
+		return strings.Split(m.inspectData, "\n")
+	}
+
+	info := m.inspectInfo
+	if info.ContainerJSONBase == nil || info.Config == nil {
+		return []string{"No details available"}
+	}
+	width := max(m.width-2, 20)
+	switch tab {
+	case "Overview":
+		state := info.State
+		if state == nil {
+			state = &container.State{}
+		}
+		status := state.Status
+		if state.Status == "exited" {
+			status += fmt.Sprintf(" (exit code %d)", state.ExitCode)
+		}
+		if state.OOMKilled {
+			status += ", OOM killed"
+		}
+		if state.Health != nil {
+			status += ", " + string(state.Health.Status)
+		}
+		pid := ""
+		if state.Pid != 0 {
+			pid = strconv.Itoa(state.Pid)
+		}
+		fields := [][2]string{
+			{"Name", strings.TrimPrefix(info.Name, "/")},
+			{"ID", info.ID},
+			{"Image", info.Config.Image},
+			{"Image ID", info.Image},
+			{"Created", info.Created},
+			{"Status", status},
+			{"Started", state.StartedAt},
+			{"Finished", strings.TrimPrefix(state.FinishedAt, "0001-01-01T00:00:00Z")},
+			{"PID", pid},
+			{"Command", formatCommand(append([]string{info.Path}, info.Args...))},
+			{"Working dir", info.Config.WorkingDir},
+			{"User", info.Config.User},
+			{"Hostname", info.Config.Hostname},
+			{"Restarts", strconv.Itoa(info.RestartCount)},
+			{"Platform", info.Platform},
+			{"Storage driver", info.Driver},
+			{"Compose project", info.Config.Labels["com.docker.compose.project"]},
+		}
+		if hc := info.HostConfig; hc != nil {
+			if hc.RestartPolicy.Name != "" {
+				fields = append(fields, [2]string{"Restart policy", string(hc.RestartPolicy.Name)})
+			}
+			if hc.Memory > 0 {
+				fields = append(fields, [2]string{"Memory limit", formatBytes(float64(hc.Memory), true)})
+			}
+			if hc.NanoCPUs > 0 {
+				fields = append(fields, [2]string{"CPUs", strconv.FormatFloat(float64(hc.NanoCPUs)/1e9, 'f', -1, 64)})
+			}
+		}
+		return fieldLines(fields)
+
+	case "Env":
+		if len(info.Config.Env) == 0 {
+			return []string{"No environment variables"}
+		}
+		var rows [][]string
+		for _, env := range info.Config.Env {
+			key, value, _ := strings.Cut(env, "=")
+			rows = append(rows, []string{key, value})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		return tableLines(width, []string{"NAME", "VALUE"}, rows)
+
+	case "Mounts":
+		if len(info.Mounts) == 0 {
+			return []string{"No mounts"}
+		}
+		var rows [][]string
+		for _, mp := range info.Mounts {
+			source := mp.Source
+			if mp.Name != "" {
+				source = mp.Name
+			}
+			access := "rw"
+			if !mp.RW {
+				access = "ro"
+			}
+			rows = append(rows, []string{string(mp.Type), access, source, mp.Destination})
+		}
+		return tableLines(width, []string{"TYPE", "MODE", "SOURCE", "DESTINATION"}, rows)
+
+	case "Network":
+		var lines []string
+		if hc := info.HostConfig; hc != nil {
+			lines = fieldLines([][2]string{
+				{"Network mode", string(hc.NetworkMode)},
+				{"DNS", strings.Join(hc.DNS, ", ")},
+				{"Extra hosts", strings.Join(hc.ExtraHosts, ", ")},
+			})
+		}
+		if info.NetworkSettings == nil || len(info.NetworkSettings.Networks) == 0 {
+			return append(lines, "", "Not connected to any network")
+		}
+		var rows [][]string
+		for _, name := range slices.Sorted(maps.Keys(info.NetworkSettings.Networks)) {
+			ep := info.NetworkSettings.Networks[name]
+			if ep == nil {
+				continue
+			}
+			ip := ep.IPAddress
+			if ep.IPPrefixLen > 0 {
+				ip += "/" + strconv.Itoa(ep.IPPrefixLen)
+			}
+			rows = append(rows, []string{name, ip, ep.Gateway, ep.MacAddress, strings.Join(ep.Aliases, ", ")})
+		}
+		return append(append(lines, ""), tableLines(width, []string{"NETWORK", "IP ADDRESS", "GATEWAY", "MAC", "ALIASES"}, rows)...)
+
+	case "Ports":
+		var ports nat.PortMap
+		if info.NetworkSettings != nil {
+			ports = info.NetworkSettings.Ports
+		}
+		if len(ports) == 0 {
+			return []string{"No exposed ports"}
+		}
+		keys := slices.Collect(maps.Keys(ports))
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].Int() != keys[j].Int() {
+				return keys[i].Int() < keys[j].Int()
+			}
+			return keys[i].Proto() < keys[j].Proto()
+		})
+		var rows [][]string
+		for _, port := range keys {
+			if len(ports[port]) == 0 {
+				rows = append(rows, []string{string(port), "(not published)"})
+			}
+			for _, binding := range ports[port] {
+				host := binding.HostIP
+				if host == "" {
+					host = "0.0.0.0"
 				}
-				// Clear status after 3 seconds
-				return m, clearStatusAfterDelay(3 * time.Second)
-			case "/":
-				// Open fuzzy search
-				m.currentView = viewSearch
-				m.searchInput = ""
-				m.searchCursor = 0
-				m.updateSearchResults() // Initialize with all results
+				rows = append(rows, []string{string(port), net.JoinHostPort(host, binding.HostPort)})
+			}
+		}
+		return tableLines(width, []string{"CONTAINER", "HOST"}, rows)
+
+	case "Labels":
+		if len(info.Config.Labels) == 0 {
+			return []string{"No labels"}
+		}
+		return tableLines(width, []string{"KEY", "VALUE"}, sortedPairs(info.Config.Labels))
+
+	case "Health":
+		var lines []string
+		if hc := info.Config.Healthcheck; hc != nil && len(hc.Test) > 0 {
+			test := hc.Test
+			if test[0] == "CMD" || test[0] == "CMD-SHELL" {
+				test = test[1:]
+			}
+			lines = fieldLines([][2]string{
+				{"Test", strings.Join(test, " ")},
+				{"Interval", durationOrEmpty(hc.Interval)},
+				{"Timeout", durationOrEmpty(hc.Timeout)},
+				{"Retries", strconv.Itoa(hc.Retries)},
+			})
+		}
+		if info.State == nil || info.State.Health == nil {
+			if len(lines) == 0 {
+				return []string{"No health check configured"}
+			}
+			return append(lines, "", "No health results yet")
+		}
+		health := info.State.Health
+		lines = append(lines, fieldLines([][2]string{
+			{"Status", string(health.Status)},
+			{"Failing streak", strconv.Itoa(health.FailingStreak)},
+		})...)
+		if len(health.Log) == 0 {
+			return lines
+		}
+		var rows [][]string
+		for i := len(health.Log) - 1; i >= 0; i-- {
+			result := health.Log[i]
+			if result == nil {
+				continue
+			}
+			output := strings.Join(strings.Fields(result.Output), " ")
+			rows = append(rows, []string{result.Start.Local().Format("2006-01-02 15:04:05"), strconv.Itoa(result.ExitCode),
+				result.End.Sub(result.Start).Round(time.Millisecond).String(), output})
+		}
+		return append(append(lines, ""), tableLines(width, []string{"STARTED", "EXIT", "TOOK", "OUTPUT"}, rows)...)
+	}
+	return nil
+}
+
+// durationOrEmpty formats a health check duration, leaving unset ones empty
+func durationOrEmpty(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// inspectPageSize is how many lines of a tab are shown at once
+func (m Model) inspectPageSize() int {
+	return max(m.height-10, 5)
+}
+
+// handleInspectKey handles keys in the inspect view
+func (m *Model) handleInspectKey(msg tea.KeyMsg) tea.Cmd {
+	tabs := m.inspectTabNames()
+	raw := tabs[m.inspectTab] == "Raw JSON"
+
+	if m.inspectQueryPrompt {
+		switch msg.String() {
+		case "esc":
+			m.inspectQueryPrompt = false
+		case "enter":
+			m.inspectQueryPrompt = false
+			m.runInspectQuery(m.inspectQueryInput)
+		case "backspace":
+			if len(m.inspectQueryInput) > 0 {
+				m.inspectQueryInput = m.inspectQueryInput[:len(m.inspectQueryInput)-1]
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				m.inspectQueryInput += string(msg.Runes)
+			}
+		}
+		return nil
+	}
+
+	last := max(len(m.inspectTabLines())-m.inspectPageSize(), 0)
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = m.inspectReturn
+		m.inspectData = ""
+		m.inspectInfo = nil
+	case "tab", "right", "l":
+		m.inspectTab = (m.inspectTab + 1) % len(tabs)
+		m.inspectOffset = 0
+	case "shift+tab", "left", "h":
+		m.inspectTab = (m.inspectTab + len(tabs) - 1) % len(tabs)
+		m.inspectOffset = 0
+	case "up", "k":
+		m.inspectOffset = max(m.inspectOffset-1, 0)
+	case "down", "j":
+		m.inspectOffset = min(m.inspectOffset+1, last)
+	case "pgup":
+		m.inspectOffset = max(m.inspectOffset-m.inspectPageSize(), 0)
+	case "pgdown", " ":
+		m.inspectOffset = min(m.inspectOffset+m.inspectPageSize(), last)
+	case "home", "g":
+		m.inspectOffset = 0
+	case "end", "G":
+		m.inspectOffset = last
+	case "/":
+		if raw {
+			m.inspectQueryPrompt = true
+			m.inspectQueryInput = m.inspectQuery
+		}
+	default:
+		// Digits jump to a tab
+		if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(tabs) {
+			m.inspectTab = n - 1
+			m.inspectOffset = 0
+		}
+	}
+	return nil
+}
+
+// imageInfo holds display information about one image reference
+type imageInfo struct {
+	ID         string // Short image ID
+	FullID     string // Full image ID ("sha256:...")
+	Ref        string // "repo:tag", or "<none>:<none>" for dangling images
+	Size       int64  // Bytes
+	Created    time.Time
+	Containers []string // Names of containers using the image
+	Dangling   bool     // Untagged image
+}
+
+// imagesLoadedMsg is sent when images are loaded from Docker
+type imagesLoadedMsg struct {
+	images []imageInfo
+	err    error
+}
+
+// imageOperationMsg is sent when an image operation completes
+type imageOperationMsg struct {
+	success bool
+	message string
+}
+
+// newImageInfos converts an image summary into one row per tag, noting
+// which of the known containers use it
+func newImageInfos(img image.Summary, containers []containerInfo) []imageInfo {
+	var users []string
+	for _, c := range containers {
+		if c.ImageID == img.ID {
+			users = append(users, c.Name)
+		}
+	}
+
+	base := imageInfo{
+		ID:         shortID(strings.TrimPrefix(img.ID, "sha256:")),
+		FullID:     img.ID,
+		Size:       img.Size,
+		Created:    time.Unix(img.Created, 0),
+		Containers: users,
+	}
+
+	var infos []imageInfo
+	for _, tag := range img.RepoTags {
+		if tag == "<none>:<none>" {
+			continue
+		}
+		info := base
+		info.Ref = tag
+		infos = append(infos, info)
+	}
+	if len(infos) == 0 {
+		base.Ref = "<none>:<none>"
+		base.Dangling = true
+		infos = append(infos, base)
+	}
+	return infos
+}
+
+// loadImages fetches images from Docker API
+func (m Model) loadImages() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	containers := m.allContainers
+	return func() tea.Msg {
+		summaries, err := cli.ImageList(ctx, image.ListOptions{})
+		if err != nil {
+			return imagesLoadedMsg{err: err}
+		}
+
+		var images []imageInfo
+		for _, img := range summaries {
+			images = append(images, newImageInfos(img, containers)...)
+		}
+		sort.SliceStable(images, func(i, j int) bool {
+			return images[i].Created.After(images[j].Created)
+		})
+		return imagesLoadedMsg{images: images}
+	}
+}
+
+// openImages switches to the images view and loads the image list
+func (m *Model) openImages() tea.Cmd {
+	m.currentView = viewImages
+	m.imagesLoading = true
+	m.statusMsg = ""
+	return m.loadImages()
+}
+
+// selectedImage returns the image row under the cursor
+func (m Model) selectedImage() (imageInfo, bool) {
+	if m.imagesCursor >= len(m.images) {
+		return imageInfo{}, false
+	}
+	return m.images[m.imagesCursor], true
+}
+
+// removeImage removes the selected image reference. Removing a tag only
+// untags the image if it has others; force also removes images used by
+// stopped containers.
+func (m Model) removeImage(force bool) tea.Cmd {
+	img, ok := m.selectedImage()
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		if !ok {
+			return imageOperationMsg{false, "No image selected"}
+		}
+		ref := img.Ref
+		if img.Dangling {
+			ref = img.FullID
+		}
+		if _, err := cli.ImageRemove(ctx, ref, image.RemoveOptions{Force: force, PruneChildren: true}); err != nil {
+			return imageOperationMsg{false, fmt.Sprintf("Failed to remove image: %v", err)}
+		}
+		return imageOperationMsg{true, fmt.Sprintf("Removed image %s", ref)}
+	}
+}
+
+// tagImage adds a new repo:tag reference to the selected image
+func (m Model) tagImage(target string) tea.Cmd {
+	img, ok := m.selectedImage()
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		if !ok {
+			return imageOperationMsg{false, "No image selected"}
+		}
+		if err := cli.ImageTag(ctx, img.FullID, target); err != nil {
+			return imageOperationMsg{false, fmt.Sprintf("Failed to tag image: %v", err)}
+		}
+		return imageOperationMsg{true, fmt.Sprintf("Tagged %s as %s", img.ID, target)}
+	}
+}
+
+// inspectImage retrieves detailed information about the selected image
+func (m Model) inspectImage() tea.Cmd {
+	img, ok := m.selectedImage()
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		if !ok {
+			return inspectDataMsg{err: fmt.Errorf("no image selected"), returnTo: viewImages}
+		}
+		inspect, err := cli.ImageInspect(ctx, img.FullID)
+		if err != nil {
+			return inspectDataMsg{err: err, returnTo: viewImages}
+		}
+		data, err := json.MarshalIndent(inspect, "", "  ")
+		if err != nil {
+			return inspectDataMsg{err: err, returnTo: viewImages}
+		}
+		return inspectDataMsg{data: string(data), title: "🔍 Image Inspection: " + img.Ref, returnTo: viewImages}
+	}
+}
+
+// handleImagesKey handles keys in the images view
+func (m *Model) handleImagesKey(msg tea.KeyMsg) tea.Cmd {
+	if m.confirmingImageRemove {
+		switch msg.String() {
+		case "y", "Y":
+			m.confirmingImageRemove = false
+			m.statusMsg = "Removing image..."
+			return m.removeImage(m.imageRemoveForce)
+		case "n", "N", "esc":
+			m.confirmingImageRemove = false
+			m.statusMsg = "Remove cancelled"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		return nil
+	}
+
+	if m.imageTagPrompt {
+		switch msg.String() {
+		case "esc":
+			m.imageTagPrompt = false
+		case "enter":
+			m.imageTagPrompt = false
+			if m.imageTagInput == "" {
+				return nil
+			}
+			m.statusMsg = "Tagging image..."
+			return m.tagImage(m.imageTagInput)
+		case "backspace":
+			if len(m.imageTagInput) > 0 {
+				m.imageTagInput = m.imageTagInput[:len(m.imageTagInput)-1]
+			}
+		default:
+			if msg.Type == tea.KeyRunes {
+				m.imageTagInput += string(msg.Runes)
+			}
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = viewList
+		m.statusMsg = ""
+	case "up", "k":
+		if m.imagesCursor > 0 {
+			m.imagesCursor--
+		}
+	case "down", "j":
+		if m.imagesCursor < len(m.images)-1 {
+			m.imagesCursor++
+		}
+	case "r", "f5":
+		m.imagesLoading = true
+		return m.loadImages()
+	case "d", "D":
+		// Remove (or force remove) - show confirmation dialog
+		if img, ok := m.selectedImage(); ok {
+			m.confirmingImageRemove = true
+			m.imageRemoveForce = msg.String() == "D"
+			verb := "Remove"
+			if m.imageRemoveForce {
+				verb = "Force remove"
+			}
+			m.statusMsg = fmt.Sprintf("⚠️  %s image '%s'? [y/n]", verb, img.Ref)
+		}
+	case "t":
+		if _, ok := m.selectedImage(); ok {
+			m.imageTagPrompt = true
+			m.imageTagInput = ""
+		}
+	case "i":
+		m.statusMsg = "Loading inspection data..."
+		return m.inspectImage()
+	case "p":
+		// Pull, starting from the selected reference to update it
+		ref := ""
+		if img, ok := m.selectedImage(); ok && !img.Dangling {
+			ref = img.Ref
+		}
+		m.openPull(ref)
+	case "n":
+		// Run a container from the selected image
+		if img, ok := m.selectedImage(); ok {
+			ref := img.Ref
+			if img.Dangling {
+				ref = img.ID
+			}
+			m.openRunForm(ref)
+		}
+	}
+	return nil
+}
+
+// pullMessage is one message of the ImagePull JSON stream (the subset of
+// the docker CLI's jsonmessage.JSONMessage that lcm displays)
+type pullMessage struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress *struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+// pullLayer tracks the progress of one layer of a pull
+type pullLayer struct {
+	ID      string
+	Status  string // "Downloading", "Extracting", "Pull complete", ...
+	Current int64
+	Total   int64
+}
+
+// done reports whether the layer needs no more work
+func (l pullLayer) done() bool {
+	return l.Status == "Pull complete" || l.Status == "Already exists"
+}
+
+// pullMsg carries one decoded pull stream message
+type pullMsg struct {
+	id      int
+	message pullMessage
+}
+
+// pullEndedMsg is sent when a pull stream ends
+type pullEndedMsg struct {
+	id  int
+	err error
+}
+
+// openPull switches to the pull view with the reference prompt open
+func (m *Model) openPull(ref string) {
+	m.pullReturn = m.currentView
+	m.currentView = viewPull
+	m.pullPrompt = true
+	m.pullInput = ref
+	m.statusMsg = ""
+}
+
+// startPull begins pulling the reference typed into the prompt
+func (m *Model) startPull() tea.Cmd {
+	if m.pullCancel != nil {
+		m.pullCancel()
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.pullID++
+	m.pullRef = m.pullInput
+	m.pullCancel = cancel
+	m.pullCh = make(chan tea.Msg)
+	m.pullLayers = nil
+	m.pullLog = nil
+	m.pullErr = nil
+	m.pullRunning = true
+	return tea.Batch(m.streamPull(ctx, m.pullID, m.pullRef, m.pullCh), waitForPull(m.pullCh))
+}
+
+// cancelPull stops the running pull, if any
+func (m *Model) cancelPull() {
+	if m.pullCancel != nil {
+		m.pullCancel()
+		m.pullCancel = nil
+	}
+}
+
+// streamPull runs ImagePull and decodes its JSON progress stream into ch
+func (m Model) streamPull(ctx context.Context, id int, ref string, ch chan<- tea.Msg) tea.Cmd {
+	cli := m.dockerClient
+	return func() tea.Msg {
+		go func() {
+			send := func(msg tea.Msg) bool {
+				select {
+				case ch <- msg:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
+			// The final message must arrive even after a cancel
+			end := func(err error) {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				ch <- pullEndedMsg{id: id, err: err}
+			}
+
+			body, err := cli.ImagePull(ctx, ref, image.PullOptions{})
+			if err != nil {
+				end(err)
+				return
+			}
+			defer body.Close()
+
+			decoder := json.NewDecoder(body)
+			for {
+				var message pullMessage
+				if err := decoder.Decode(&message); err != nil {
+					if err == io.EOF {
+						err = nil
+					}
+					end(err)
+					return
+				}
+				if message.Error != nil {
+					end(fmt.Errorf("%s", message.Error.Message))
+					return
+				}
+				if !send(pullMsg{id: id, message: message}) {
+					end(nil)
+					return
+				}
+			}
+		}()
+		return nil
+	}
+}
+
+// waitForPull returns a command that blocks until the next pull message arrives
+func waitForPull(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// applyPullMessage records a pull stream message against its layer, or in
+// the log when it isn't about a layer
+func (m *Model) applyPullMessage(message pullMessage) {
+	if message.ID == "" || strings.HasPrefix(message.Status, "Pulling from") {
+		m.pullLog = append(m.pullLog, strings.TrimSpace(message.ID+" "+message.Status))
+		return
+	}
+
+	i := 0
+	for i < len(m.pullLayers) && m.pullLayers[i].ID != message.ID {
+		i++
+	}
+	if i == len(m.pullLayers) {
+		m.pullLayers = append(m.pullLayers, pullLayer{ID: message.ID})
+	}
+	layer := &m.pullLayers[i]
+	layer.Status = message.Status
+	layer.Current, layer.Total = 0, 0
+	if message.Progress != nil {
+		layer.Current, layer.Total = message.Progress.Current, message.Progress.Total
+	}
+}
+
+// progressBar renders current/total as a bar of the given width
+func progressBar(current, total int64, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = int(min(current, total) * int64(width) / total)
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// handlePullKey handles keys in the pull view
+func (m *Model) handlePullKey(msg tea.KeyMsg) tea.Cmd {
+	if m.pullPrompt {
+		switch msg.String() {
+		case "esc":
+			m.pullPrompt = false
+			if m.pullRef == "" {
+				m.currentView = m.pullReturn
+			}
+		case "enter":
+			m.pullInput = strings.TrimSpace(m.pullInput)
+			if m.pullInput == "" {
+				return nil
+			}
+			m.pullPrompt = false
+			return m.startPull()
+		case "backspace":
+			if len(m.pullInput) > 0 {
+				m.pullInput = m.pullInput[:len(m.pullInput)-1]
+			}
+		default:
+			if msg.Type == tea.KeyRunes {
+				m.pullInput += string(msg.Runes)
+			}
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		if m.pullRunning {
+			// First press cancels the pull, the next one leaves
+			m.cancelPull()
+			m.statusMsg = "Cancelling pull..."
+			return nil
+		}
+		m.currentView = m.pullReturn
+		m.pullRef = ""
+		m.statusMsg = ""
+		if m.currentView == viewImages {
+			m.imagesLoading = true
+			return m.loadImages()
+		}
+	case "p":
+		if !m.pullRunning {
+			m.pullPrompt = true
+			m.pullInput = m.pullRef
+		}
+	}
+	return nil
+}
+
+// runField is one input of the run form
+type runField int
+
+const (
+	runImage runField = iota
+	runName
+	runPorts
+	runEnv
+	runVolumes
+	runNetwork
+	runRestart
+	runCommand
+	runLabels
+	runFieldCount
+)
+
+// runFields labels each run form input, with a hint on its format
+var runFields = [runFieldCount]struct{ label, hint string }{
+	{"Image", "e.g. nginx:alpine"},
+	{"Name", "optional"},
+	{"Ports", "[ip:]host:container[/proto], comma-separated"},
+	{"Env", "KEY=value, comma-separated (\\, for a literal comma)"},
+	{"Volumes", "volume:/path or /host/path:/path[:ro], comma-separated"},
+	{"Network", "network name (default bridge)"},
+	{"Restart", "no, always, unless-stopped or on-failure[:N]"},
+	{"Command", "overrides the image command; quotes allowed"},
+	{"Labels", "key=value, comma-separated"},
+}
+
+// runSpec is a container configuration made of the `docker run` options
+// lcm knows how to create containers with
+type runSpec struct {
+	Image      string
+	Name       string
+	Ports      []string
+	Env        []string
+	Volumes    []string
+	Mounts     []mount.Mount // --mount options that can't be written as -v
+	Network    string
+	Restart    string
+	Entrypoint []string // Overrides the image entrypoint when set
+	Command    []string
+	Labels     []string
+	User       string
+	WorkingDir string
+	Memory     int64 // Bytes
+	NanoCPUs   int64
+	CPUShares  int64
+	PidsLimit  int64
+}
+
+// runResultMsg is sent when creating and starting a container completes
+type runResultMsg struct {
+	created bool // Whether the container exists now, even if it didn't start
+	message string
+}
+
+// splitList splits a comma-separated form value, dropping empty items.
+// A comma inside an item is written as "\,".
+func splitList(value string) []string {
+	var items []string
+	var current strings.Builder
+	flush := func() {
+		if item := strings.TrimSpace(current.String()); item != "" {
+			items = append(items, item)
+		}
+		current.Reset()
+	}
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value) && value[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case value[i] == ',':
+			flush()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	flush()
+	return items
+}
+
+// joinList is the inverse of splitList
+func joinList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = strings.ReplaceAll(item, ",", "\\,")
+	}
+	return strings.Join(escaped, ", ")
+}
+
+// splitArgs splits a command line into arguments the way a POSIX shell
+// would, honouring single quotes, double quotes and backslash escapes
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in command")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// shellQuote quotes s for a POSIX shell when it contains special characters
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r)) {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		}
+	}
+	return s
+}
+
+// parseRunForm turns run form values into a runSpec, validating them
+func parseRunForm(values [runFieldCount]string) (runSpec, error) {
+	spec := runSpec{
+		Image:   strings.TrimSpace(values[runImage]),
+		Name:    strings.TrimSpace(values[runName]),
+		Ports:   splitList(values[runPorts]),
+		Env:     splitList(values[runEnv]),
+		Volumes: splitList(values[runVolumes]),
+		Network: strings.TrimSpace(values[runNetwork]),
+		Restart: strings.TrimSpace(values[runRestart]),
+		Labels:  splitList(values[runLabels]),
+	}
+	if spec.Image == "" {
+		return spec, fmt.Errorf("an image is required")
+	}
+	command, err := splitArgs(values[runCommand])
+	if err != nil {
+		return spec, err
+	}
+	spec.Command = command
+	_, _, err = spec.containerConfig()
+	return spec, err
+}
+
+// parseRestartPolicy parses a --restart value such as "on-failure:3"
+func parseRestartPolicy(value string) (container.RestartPolicy, error) {
+	var policy container.RestartPolicy
+	if value == "" {
+		return policy, nil
+	}
+	name, count, hasCount := strings.Cut(value, ":")
+	policy.Name = container.RestartPolicyMode(name)
+	if hasCount {
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return policy, fmt.Errorf("invalid restart retry count %q", count)
+		}
+		policy.MaximumRetryCount = n
+	}
+	return policy, container.ValidateRestartPolicy(policy)
+}
+
+// containerConfig builds the ContainerCreate configuration for the spec
+func (spec runSpec) containerConfig() (*container.Config, *container.HostConfig, error) {
+	exposed, bindings, err := nat.ParsePortSpecs(spec.Ports)
+	if err != nil {
+		return nil, nil, err
+	}
+	restart, err := parseRestartPolicy(spec.Restart)
+	if err != nil {
+		return nil, nil, err
+	}
+	var labels map[string]string
+	for _, label := range spec.Labels {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		key, value, _ := strings.Cut(label, "=")
+		labels[key] = value
+	}
+
+	config := &container.Config{
+		Image:        spec.Image,
+		Env:          spec.Env,
+		Entrypoint:   spec.Entrypoint,
+		Cmd:          spec.Command,
+		Labels:       labels,
+		ExposedPorts: exposed,
+		User:         spec.User,
+		WorkingDir:   spec.WorkingDir,
+	}
+	hostConfig := &container.HostConfig{
+		Binds:         spec.Volumes,
+		Mounts:        spec.Mounts,
+		PortBindings:  bindings,
+		RestartPolicy: restart,
+		NetworkMode:   container.NetworkMode(spec.Network),
+	}
+	hostConfig.Memory = spec.Memory
+	hostConfig.NanoCPUs = spec.NanoCPUs
+	hostConfig.CPUShares = spec.CPUShares
+	if spec.PidsLimit > 0 {
+		hostConfig.PidsLimit = &spec.PidsLimit
+	}
+	return config, hostConfig, nil
+}
+
+// dockerRunGroups returns the `docker run` command line equivalent to the
+// spec, one option (with its value) per group
+func (spec runSpec) dockerRunGroups() [][]string {
+	groups := [][]string{{"docker", "run", "-d"}}
+	add := func(args ...string) {
+		groups = append(groups, args)
+	}
+	if spec.Name != "" {
+		add("--name", spec.Name)
+	}
+	for _, port := range spec.Ports {
+		add("-p", port)
+	}
+	for _, env := range spec.Env {
+		add("-e", env)
+	}
+	for _, v := range spec.Volumes {
+		add("-v", v)
+	}
+	for _, m := range spec.Mounts {
+		add("--mount", formatMount(m))
+	}
+	if spec.Network != "" {
+		add("--network", spec.Network)
+	}
+	if spec.Restart != "" {
+		add("--restart", spec.Restart)
+	}
+	for _, label := range spec.Labels {
+		add("-l", label)
+	}
+	if spec.User != "" {
+		add("--user", spec.User)
+	}
+	if spec.WorkingDir != "" {
+		add("--workdir", spec.WorkingDir)
+	}
+	if spec.Memory > 0 {
+		add("--memory", formatMemory(spec.Memory))
+	}
+	if spec.NanoCPUs > 0 {
+		add("--cpus", strconv.FormatFloat(float64(spec.NanoCPUs)/1e9, 'f', -1, 64))
+	}
+	if spec.CPUShares > 0 {
+		add("--cpu-shares", strconv.FormatInt(spec.CPUShares, 10))
+	}
+	if spec.PidsLimit > 0 {
+		add("--pids-limit", strconv.FormatInt(spec.PidsLimit, 10))
+	}
+
+	// --entrypoint takes a single executable; the rest of the entrypoint
+	// goes in front of the command
+	command := spec.Command
+	if len(spec.Entrypoint) > 0 {
+		add("--entrypoint", spec.Entrypoint[0])
+		command = append(append([]string{}, spec.Entrypoint[1:]...), spec.Command...)
+	}
+	add(append([]string{spec.Image}, command...)...)
+	return groups
+}
+
+// dockerRunArgs returns the `docker run` command line equivalent to the spec
+func (spec runSpec) dockerRunArgs() []string {
+	var args []string
+	for _, group := range spec.dockerRunGroups() {
+		args = append(args, group...)
+	}
+	return args
+}
+
+// formatMount renders a mount as a --mount value
+func formatMount(m mount.Mount) string {
+	parts := []string{"type=" + string(m.Type)}
+	if m.Source != "" {
+		parts = append(parts, "source="+m.Source)
+	}
+	parts = append(parts, "target="+m.Target)
+	if m.ReadOnly {
+		parts = append(parts, "readonly")
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatMemory renders a byte count the way --memory accepts it
+func formatMemory(n int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}} {
+		if n%unit.size == 0 {
+			return fmt.Sprintf("%d%s", n/unit.size, unit.suffix)
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// formatCommand joins arguments into a line that can be pasted into a shell
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// formatCommandLines renders groups of arguments one per line, joined
+// with shell line continuations
+func formatCommandLines(groups [][]string) string {
+	lines := make([]string, len(groups))
+	for i, group := range groups {
+		lines[i] = formatCommand(group)
+	}
+	return strings.Join(lines, " \\\n    ")
+}
+
+// imageDefaults holds the image configuration a container inherits unless
+// it is overridden at `docker run` time
+type imageDefaults struct {
+	Env        []string
+	Entrypoint []string
+	Cmd        []string
+	Labels     map[string]string
+	User       string
+	WorkingDir string
+}
+
+// runSpecFromInspect reconstructs the `docker run` options that produced
+// a container, leaving out whatever the image already provides
+func runSpecFromInspect(info container.InspectResponse, defaults imageDefaults) runSpec {
+	spec := runSpec{Name: strings.TrimPrefix(info.Name, "/")}
+	if info.Config != nil {
+		spec.Image = info.Config.Image
+
+		imageEnv := make(map[string]bool)
+		for _, env := range defaults.Env {
+			imageEnv[env] = true
+		}
+		for _, env := range info.Config.Env {
+			if !imageEnv[env] {
+				spec.Env = append(spec.Env, env)
+			}
+		}
+
+		for key, value := range info.Config.Labels {
+			if imageValue, ok := defaults.Labels[key]; !ok || imageValue != value {
+				spec.Labels = append(spec.Labels, key+"="+value)
+			}
+		}
+		sort.Strings(spec.Labels)
+
+		// Overriding the entrypoint resets the image command, so the
+		// command is needed in full whenever the entrypoint changed
+		if !slices.Equal(info.Config.Entrypoint, defaults.Entrypoint) {
+			spec.Entrypoint = info.Config.Entrypoint
+			spec.Command = info.Config.Cmd
+		} else if !slices.Equal(info.Config.Cmd, defaults.Cmd) {
+			spec.Command = info.Config.Cmd
+		}
+
+		if info.Config.User != defaults.User {
+			spec.User = info.Config.User
+		}
+		if info.Config.WorkingDir != defaults.WorkingDir {
+			spec.WorkingDir = info.Config.WorkingDir
+		}
+	}
+
+	if hc := info.HostConfig; hc != nil {
+		spec.Volumes = hc.Binds
+		spec.Mounts = hc.Mounts
+
+		for port, bindings := range hc.PortBindings {
+			containerPort := port.Port()
+			if port.Proto() != "tcp" {
+				containerPort = string(port)
+			}
+			for _, b := range bindings {
+				var parts []string
+				if b.HostIP != "" && b.HostIP != "0.0.0.0" {
+					parts = append(parts, b.HostIP)
+				}
+				if b.HostPort != "" || len(parts) > 0 {
+					parts = append(parts, b.HostPort)
+				}
+				spec.Ports = append(spec.Ports, strings.Join(append(parts, containerPort), ":"))
+			}
+		}
+		sort.Strings(spec.Ports)
+
+		if mode := string(hc.NetworkMode); mode != "default" && mode != "bridge" {
+			spec.Network = mode
+		}
+		if policy := hc.RestartPolicy; policy.Name != "" && policy.Name != container.RestartPolicyDisabled {
+			spec.Restart = string(policy.Name)
+			if policy.MaximumRetryCount > 0 {
+				spec.Restart += ":" + strconv.Itoa(policy.MaximumRetryCount)
+			}
+		}
+
+		spec.Memory = hc.Memory
+		spec.NanoCPUs = hc.NanoCPUs
+		spec.CPUShares = hc.CPUShares
+		if hc.PidsLimit != nil {
+			spec.PidsLimit = *hc.PidsLimit
+		}
+	}
+	return spec
+}
+
+// runCommandMsg is sent when the docker run command for a container is ready
+type runCommandMsg struct {
+	name    string
+	command runSpec
+	err     error
+}
+
+// generateRunCommand inspects the selected container and its image and
+// reconstructs the docker run command that created it
+func (m Model) generateRunCommand() tea.Cmd {
+	if len(m.containers) == 0 || m.cursorProject != "" {
+		return func() tea.Msg {
+			return runCommandMsg{err: fmt.Errorf("no container selected")}
+		}
+	}
+	cli, ctx := m.dockerClient, m.ctx
+	c := m.containers[m.cursor]
+	return func() tea.Msg {
+		info, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return runCommandMsg{name: c.Name, err: err}
+		}
+
+		var defaults imageDefaults
+		img, err := cli.ImageInspect(ctx, info.Image)
+		if err == nil && img.Config != nil {
+			defaults = imageDefaults{
+				Env:        img.Config.Env,
+				Entrypoint: img.Config.Entrypoint,
+				Cmd:        img.Config.Cmd,
+				Labels:     img.Config.Labels,
+				User:       img.Config.User,
+				WorkingDir: img.Config.WorkingDir,
+			}
+		}
+		// Without the image (it may have been removed) nothing is
+		// omitted, which is verbose but still correct
+		return runCommandMsg{name: c.Name, command: runSpecFromInspect(info, defaults)}
+	}
+}
+
+// copyResultMsg reports the outcome of copying to the clipboard
+type copyResultMsg struct {
+	message string
+}
+
+// copyToClipboard copies text using the platform clipboard tool, falling
+// back to the OSC 52 terminal escape sequence
+func copyToClipboard(text string) tea.Cmd {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "linux":
+		candidates = [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	}
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err != nil {
+			continue
+		}
+		return func() tea.Msg {
+			cmd := exec.Command(candidate[0], candidate[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err != nil {
+				return copyResultMsg{fmt.Sprintf("Failed to copy: %v", err)}
+			}
+			return copyResultMsg{"Copied to clipboard"}
+		}
+	}
+
+	// The escape sequence goes through tea.Exec so it is written to the
+	// program's output between frames rather than racing the renderer
+	return tea.Exec(&osc52Copy{text: text}, func(err error) tea.Msg {
+		if err != nil {
+			return copyResultMsg{fmt.Sprintf("Failed to copy: %v", err)}
+		}
+		return copyResultMsg{"Copied to clipboard (via terminal)"}
+	})
+}
+
+// osc52Copy is a tea.ExecCommand that asks the terminal to set the
+// clipboard with an OSC 52 escape sequence
+type osc52Copy struct {
+	text   string
+	stdout io.Writer
+}
+
+// Run writes the escape sequence to the program's output
+func (c *osc52Copy) Run() error {
+	_, err := fmt.Fprintf(c.stdout, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(c.text)))
+	return err
+}
+
+func (c *osc52Copy) SetStdin(io.Reader)    {}
+func (c *osc52Copy) SetStdout(w io.Writer) { c.stdout = w }
+func (c *osc52Copy) SetStderr(io.Writer)   {}
+
+// composeService is one container to export as a compose service
+type composeService struct {
+	Name          string   // Service name
+	ContainerName string   // Set for containers not created by compose
+	Spec          runSpec  // Options reconstructed from inspect data
+	Networks      []string // User-defined networks the container is attached to
+}
+
+// composeExportMsg is sent when a compose export completes
+type composeExportMsg struct {
+	success bool
+	message string
+}
+
+// yamlPlain matches strings that can be written as plain YAML scalars
+var yamlPlain = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_./@=+:-]*$`)
+
+// yamlString renders s as a YAML scalar, quoting it when a plain scalar
+// would be misread
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off", "true", "false", "null":
+	default:
+		if yamlPlain.MatchString(s) && !strings.HasSuffix(s, ":") {
+			return s
+		}
+	}
+	// A JSON string is a valid double-quoted YAML scalar
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// yamlValue renders s as a YAML scalar for a compose value. Compose
+// interpolates $VAR and ${VAR} even inside quotes, so $ is escaped as $$.
+func yamlValue(s string) string {
+	return yamlString(strings.ReplaceAll(s, "$", "$$"))
+}
+
+// yamlList renders items as a YAML flow sequence of compose values
+func yamlList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = yamlValue(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// namedVolume returns the volume name a -v value mounts, if it isn't a bind mount
+func namedVolume(bind string) (string, bool) {
+	source, _, ok := strings.Cut(bind, ":")
+	if !ok || strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") {
+		return "", false
+	}
+	return source, true
+}
+
+// composeFile renders services as a docker-compose.yml document. Named
+// volumes and networks keep their names; services sharing a network get a
+// commented-out depends_on hint since the dependency direction is unknown.
+func composeFile(services []composeService) string {
+	var b strings.Builder
+	line := func(indent int, format string, args ...any) {
+		b.WriteString(strings.Repeat("  ", indent) + fmt.Sprintf(format, args...) + "\n")
+	}
+	volumes := make(map[string]bool)
+	networks := make(map[string]bool)
+
+	line(0, "# Generated by lcm from container inspect data")
+	line(0, "services:")
+	for i, svc := range services {
+		spec := svc.Spec
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		line(1, "%s:", yamlString(svc.Name))
+		line(2, "image: %s", yamlValue(spec.Image))
+		if svc.ContainerName != "" {
+			line(2, "container_name: %s", yamlValue(svc.ContainerName))
+		}
+		if len(spec.Entrypoint) > 0 {
+			line(2, "entrypoint: %s", yamlList(spec.Entrypoint))
+		}
+		if len(spec.Command) > 0 {
+			line(2, "command: %s", yamlList(spec.Command))
+		}
+		if spec.User != "" {
+			line(2, "user: %s", yamlValue(spec.User))
+		}
+		if spec.WorkingDir != "" {
+			line(2, "working_dir: %s", yamlValue(spec.WorkingDir))
+		}
+		if len(spec.Ports) > 0 {
+			line(2, "ports:")
+			for _, port := range spec.Ports {
+				// Always quoted: YAML 1.1 reads some "a:b" values as numbers
+				quoted, _ := json.Marshal(port)
+				line(3, "- %s", quoted)
+			}
+		}
+		if len(spec.Env) > 0 {
+			line(2, "environment:")
+			for _, env := range spec.Env {
+				line(3, "- %s", yamlValue(env))
+			}
+		}
+		if len(spec.Volumes) > 0 || len(spec.Mounts) > 0 {
+			line(2, "volumes:")
+			for _, v := range spec.Volumes {
+				if name, ok := namedVolume(v); ok {
+					volumes[name] = true
+				}
+				line(3, "- %s", yamlValue(v))
+			}
+			for _, m := range spec.Mounts {
+				if m.Type == mount.TypeVolume && m.Source != "" {
+					volumes[m.Source] = true
+				}
+				line(3, "- type: %s", m.Type)
+				if m.Source != "" {
+					line(4, "source: %s", yamlValue(m.Source))
+				}
+				line(4, "target: %s", yamlValue(m.Target))
+				if m.ReadOnly {
+					line(4, "read_only: true")
+				}
+			}
+		}
+		if spec.Network == "host" || spec.Network == "none" || strings.HasPrefix(spec.Network, "container:") {
+			line(2, "network_mode: %s", yamlValue(spec.Network))
+		} else if len(svc.Networks) > 0 {
+			line(2, "networks:")
+			for _, n := range svc.Networks {
+				networks[n] = true
+				line(3, "- %s", yamlValue(n))
+			}
+		}
+		if spec.Restart != "" {
+			line(2, "restart: %s", yamlValue(spec.Restart))
+		}
+		var labels []string
+		for _, label := range spec.Labels {
+			// Compose sets its own labels when it creates the container
+			if !strings.HasPrefix(label, "com.docker.compose.") {
+				labels = append(labels, label)
+			}
+		}
+		if len(labels) > 0 {
+			line(2, "labels:")
+			for _, label := range labels {
+				key, value, _ := strings.Cut(label, "=")
+				line(3, "%s: %s", yamlString(key), yamlValue(value))
+			}
+		}
+		if spec.Memory > 0 {
+			line(2, "mem_limit: %s", formatMemory(spec.Memory))
+		}
+		if spec.NanoCPUs > 0 {
+			line(2, "cpus: %s", strconv.FormatFloat(float64(spec.NanoCPUs)/1e9, 'f', -1, 64))
+		}
+		if spec.CPUShares > 0 {
+			line(2, "cpu_shares: %d", spec.CPUShares)
+		}
+		if spec.PidsLimit > 0 {
+			line(2, "pids_limit: %d", spec.PidsLimit)
+		}
+
+		var peers []string
+		for _, other := range services {
+			if other.Name != svc.Name && slices.ContainsFunc(other.Networks, func(n string) bool {
+				return slices.Contains(svc.Networks, n)
+			}) {
+				peers = append(peers, other.Name)
+			}
+		}
+		if len(peers) > 0 {
+			line(2, "# Shares a network with %s; uncomment the services this one depends on", strings.Join(peers, ", "))
+			line(2, "# depends_on:")
+			for _, peer := range peers {
+				line(2, "#   - %s", peer)
+			}
+		}
+	}
+
+	for _, section := range []struct {
+		name  string
+		names map[string]bool
+	}{{"volumes", volumes}, {"networks", networks}} {
+		if len(section.names) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		line(0, "%s:", section.name)
+		for _, name := range slices.Sorted(maps.Keys(section.names)) {
+			line(1, "%s:", yamlString(name))
+			line(2, "name: %s", yamlValue(name))
+		}
+	}
+	return b.String()
+}
+
+// composeServiceName turns a container or compose service name into a
+// valid, unique service name
+func composeServiceName(name string, taken map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, name)
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// exportCompose inspects the targets and writes them to path as a compose file
+func (m Model) exportCompose(targets []containerInfo, path string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		var services []composeService
+		taken := make(map[string]bool)
+		for _, c := range targets {
+			info, err := cli.ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return composeExportMsg{false, fmt.Sprintf("Failed to inspect %s: %v", c.Name, err)}
+			}
+			var defaults imageDefaults
+			if img, err := cli.ImageInspect(ctx, info.Image); err == nil && img.Config != nil {
+				defaults = imageDefaults{
+					Env:        img.Config.Env,
+					Entrypoint: img.Config.Entrypoint,
+					Cmd:        img.Config.Cmd,
+					Labels:     img.Config.Labels,
+					User:       img.Config.User,
+					WorkingDir: img.Config.WorkingDir,
+				}
+			}
+
+			svc := composeService{Spec: runSpecFromInspect(info, defaults)}
+			if c.Service != "" {
+				svc.Name = composeServiceName(c.Service, taken)
+			} else {
+				svc.Name = composeServiceName(c.Name, taken)
+				svc.ContainerName = c.Name
+			}
+			if info.NetworkSettings != nil {
+				for name := range info.NetworkSettings.Networks {
+					if !predefinedNetworks[name] {
+						svc.Networks = append(svc.Networks, name)
+					}
+				}
+				sort.Strings(svc.Networks)
+			}
+			services = append(services, svc)
+		}
+
+		if err := os.WriteFile(path, []byte(composeFile(services)), 0o644); err != nil {
+			return composeExportMsg{false, fmt.Sprintf("Failed to write %s: %v", path, err)}
+		}
+		return composeExportMsg{true, fmt.Sprintf("Exported %s to %s", pluralize(len(services), "service"), path)}
+	}
+}
+
+// handleExportKey edits the export path prompt and confirms overwriting
+// an existing file
+func (m *Model) handleExportKey(msg tea.KeyMsg) tea.Cmd {
+	if m.confirmingExportOverwrite {
+		switch msg.String() {
+		case "y", "Y":
+			m.confirmingExportOverwrite = false
+			m.statusMsg = "Exporting..."
+			return m.exportCompose(m.exportTargets, m.exportPath)
+		case "n", "N", "esc":
+			m.confirmingExportOverwrite = false
+			m.statusMsg = "Export cancelled"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.exportPrompt = false
+	case "enter":
+		m.exportPrompt = false
+		path := strings.TrimSpace(m.exportPromptInput)
+		if path == "" {
+			return nil
+		}
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, rest)
+			}
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "docker-compose.yml")
+		}
+		m.exportPath = path
+		if _, err := os.Stat(path); err == nil {
+			m.confirmingExportOverwrite = true
+			m.statusMsg = fmt.Sprintf("⚠️  %s already exists, overwrite? [y/n]", path)
+			return nil
+		}
+		m.statusMsg = "Exporting..."
+		return m.exportCompose(m.exportTargets, path)
+	case "backspace":
+		if len(m.exportPromptInput) > 0 {
+			m.exportPromptInput = m.exportPromptInput[:len(m.exportPromptInput)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.exportPromptInput += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// openExportPrompt asks where to write the compose file for the selected containers
+func (m *Model) openExportPrompt() {
+	m.exportTargets = m.actionTargets()
+	if len(m.exportTargets) == 0 {
+		m.statusMsg = "No container selected"
+		return
+	}
+	m.exportPrompt = true
+	m.exportPromptInput = "docker-compose.yml"
+}
+
+// portConflicts reports host ports the spec would publish that a container
+// already publishes
+func portConflicts(spec runSpec, containers []containerInfo) []string {
+	_, bindings, err := nat.ParsePortSpecs(spec.Ports)
+	if err != nil {
+		return nil
+	}
+
+	// containerInfo.Ports holds "host:container/proto" for published ports
+	published := make(map[string]string)
+	for _, c := range containers {
+		for _, port := range c.Ports {
+			host, rest, ok := strings.Cut(port, ":")
+			if !ok {
+				continue
+			}
+			_, proto, _ := strings.Cut(rest, "/")
+			published[host+"/"+proto] = c.Name
+		}
+	}
+
+	var conflicts []string
+	for port, portBindings := range bindings {
+		for _, b := range portBindings {
+			key := b.HostPort + "/" + port.Proto()
+			if name, ok := published[key]; ok && b.HostPort != "" {
+				conflicts = append(conflicts, fmt.Sprintf("host port %s is already published by %s", key, name))
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// openRunForm switches to the run form with the image prefilled
+func (m *Model) openRunForm(imageRef string) {
+	m.runReturn = m.currentView
+	m.currentView = viewRun
+	m.runForm = [runFieldCount]string{}
+	m.runForm[runImage] = imageRef
+	m.runRecreate = nil
+	m.runFocus = runImage
+	if imageRef != "" {
+		m.runFocus = runName
+	}
+	m.statusMsg = ""
+}
+
+// runPortConflicts checks the spec's host ports against every other
+// container; a container being recreated gives its own ports up
+func (m Model) runPortConflicts(spec runSpec) []string {
+	if m.runRecreate == nil {
+		return portConflicts(spec, m.allContainers)
+	}
+	var others []containerInfo
+	for _, c := range m.allContainers {
+		if c.ID != shortID(m.runRecreate.ID) {
+			others = append(others, c)
+		}
+	}
+	return portConflicts(spec, others)
+}
+
+// recreateTarget is a container being recreated from the run form
+type recreateTarget struct {
+	ID      string
+	Name    string
+	Running bool
+	Inspect container.InspectResponse // Settings the form doesn't show are carried over from here
+	Spec    runSpec                   // Options the form was filled from
+}
+
+// recreateLoadedMsg is sent when a container's configuration is loaded for recreating
+type recreateLoadedMsg struct {
+	target recreateTarget
+	err    error
+}
+
+// recreateGracePeriod is how long a recreated container must keep running
+// before the old one is considered safe to leave stopped
+const recreateGracePeriod = 2 * time.Second
+
+// loadRecreate inspects the selected container and its image for the recreate form
+func (m Model) loadRecreate() tea.Cmd {
+	if len(m.containers) == 0 || m.cursorProject != "" {
+		return func() tea.Msg {
+			return recreateLoadedMsg{err: fmt.Errorf("no container selected")}
+		}
+	}
+	cli, ctx := m.dockerClient, m.ctx
+	c := m.containers[m.cursor]
+	return func() tea.Msg {
+		info, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return recreateLoadedMsg{err: err}
+		}
+		// Stopping a --rm container deletes it, leaving nothing to roll back to
+		if info.HostConfig != nil && info.HostConfig.AutoRemove {
+			return recreateLoadedMsg{err: fmt.Errorf("%s was started with --rm, so stopping it would remove it; it can't be recreated safely", c.Name)}
+		}
+		var defaults imageDefaults
+		if img, err := cli.ImageInspect(ctx, info.Image); err == nil && img.Config != nil {
+			defaults = imageDefaults{
+				Env:        img.Config.Env,
+				Entrypoint: img.Config.Entrypoint,
+				Cmd:        img.Config.Cmd,
+				Labels:     img.Config.Labels,
+				User:       img.Config.User,
+				WorkingDir: img.Config.WorkingDir,
+			}
+		}
+		return recreateLoadedMsg{target: recreateTarget{
+			ID:      info.ID,
+			Name:    c.Name,
+			Running: info.State != nil && info.State.Running,
+			Inspect: info,
+			Spec:    runSpecFromInspect(info, defaults),
+		}}
+	}
+}
+
+// openRecreateForm fills the run form from a container's current configuration
+func (m *Model) openRecreateForm(target recreateTarget) {
+	m.openRunForm("")
+	spec := target.Spec
+	m.runForm = [runFieldCount]string{
+		runImage:   spec.Image,
+		runName:    spec.Name,
+		runPorts:   joinList(spec.Ports),
+		runEnv:     joinList(spec.Env),
+		runVolumes: joinList(spec.Volumes),
+		runNetwork: spec.Network,
+		runRestart: spec.Restart,
+		runCommand: formatCommand(spec.Command),
+		runLabels:  joinList(spec.Labels),
+	}
+	m.runRecreate = &target
+}
+
+// recreateConfig applies the edited options to the container's original
+// configuration, so settings the form doesn't show are kept
+func recreateConfig(info container.InspectResponse, spec runSpec) (*container.Config, *container.HostConfig, error) {
+	edited, editedHost, err := spec.containerConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := *info.Config
+	config.Image = edited.Image
+	config.Env = edited.Env
+	config.Entrypoint = edited.Entrypoint
+	config.Cmd = edited.Cmd
+	config.Labels = edited.Labels
+	config.ExposedPorts = edited.ExposedPorts
+	// The default hostname is the old container's ID
+	if config.Hostname == shortID(info.ID) {
+		config.Hostname = ""
+	}
+
+	hostConfig := *info.HostConfig
+	hostConfig.Binds = editedHost.Binds
+	hostConfig.PortBindings = editedHost.PortBindings
+	hostConfig.RestartPolicy = editedHost.RestartPolicy
+	hostConfig.NetworkMode = editedHost.NetworkMode
+	return &config, &hostConfig, nil
+}
+
+// recreateContainer stops the target, keeps it as a renamed backup and
+// creates and starts its replacement, rolling back if that fails
+func (m Model) recreateContainer(target recreateTarget, spec runSpec) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	// The form has no entrypoint field; keep an overridden one
+	spec.Entrypoint = target.Spec.Entrypoint
+	return func() tea.Msg {
+		config, hostConfig, err := recreateConfig(target.Inspect, spec)
+		if err != nil {
+			return runResultMsg{false, fmt.Sprintf("Error: %v", err)}
+		}
+		name := spec.Name
+		if name == "" {
+			name = target.Name
+		}
+		backup := fmt.Sprintf("%s-backup-%s", target.Name, time.Now().Format("20060102-150405"))
+
+		timeout := 10
+		if target.Running {
+			if err := cli.ContainerStop(ctx, target.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+				return runResultMsg{false, fmt.Sprintf("Failed to stop %s: %v", target.Name, err)}
+			}
+		}
+		if err := cli.ContainerRename(ctx, target.ID, backup); err != nil {
+			if target.Running {
+				_ = cli.ContainerStart(ctx, target.ID, container.StartOptions{})
+			}
+			return runResultMsg{false, fmt.Sprintf("Failed to rename %s: %v", target.Name, err)}
+		}
+
+		// Undo everything and bring the original container back. A failed
+		// rollback leaves the form open so the backup name stays visible.
+		var policy container.RestartPolicy
+		if target.Inspect.HostConfig != nil {
+			policy = target.Inspect.HostConfig.RestartPolicy
+		}
+		rollback := func(newID string, cause error) tea.Msg {
+			if newID != "" {
+				_ = cli.ContainerRemove(ctx, newID, container.RemoveOptions{Force: true})
+			}
+			if err := cli.ContainerRename(ctx, target.ID, target.Name); err != nil {
+				return runResultMsg{false, fmt.Sprintf("Recreate failed (%v) and so did the rollback: %v; the original is %s", cause, err, backup)}
+			}
+			if _, err := cli.ContainerUpdate(ctx, target.ID, container.UpdateConfig{RestartPolicy: policy}); err != nil {
+				return runResultMsg{false, fmt.Sprintf("Recreate failed (%v); the original is back but its restart policy couldn't be restored: %v", cause, err)}
+			}
+			if target.Running {
+				if err := cli.ContainerStart(ctx, target.ID, container.StartOptions{}); err != nil {
+					return runResultMsg{false, fmt.Sprintf("Recreate failed (%v); the original is back but failed to start: %v", cause, err)}
+				}
+			}
+			return runResultMsg{false, fmt.Sprintf("Recreate failed, rolled back to the original: %v", cause)}
+		}
+
+		// The backup mustn't come back up on a daemon restart and take the
+		// new container's ports
+		if _, err := cli.ContainerUpdate(ctx, target.ID, container.UpdateConfig{RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyDisabled}}); err != nil {
+			return rollback("", fmt.Errorf("disabling the backup's restart policy: %w", err))
+		}
+
+		resp, err := cli.ContainerCreate(ctx, config, hostConfig, nil, nil, name)
+		if err != nil {
+			return rollback("", err)
+		}
+
+		// Reattach the other user-defined networks the original was on
+		if target.Inspect.NetworkSettings != nil && !hostConfig.NetworkMode.IsHost() && !hostConfig.NetworkMode.IsNone() && !hostConfig.NetworkMode.IsContainer() {
+			for networkName, endpoint := range target.Inspect.NetworkSettings.Networks {
+				// The original's primary network is only kept if the form still names it
+				if predefinedNetworks[networkName] || networkName == string(hostConfig.NetworkMode) ||
+					networkName == string(target.Inspect.HostConfig.NetworkMode) {
+					continue
+				}
+				settings := &network.EndpointSettings{}
+				if endpoint != nil {
+					settings.Aliases = endpoint.Aliases
+				}
+				if err := cli.NetworkConnect(ctx, networkName, resp.ID, settings); err != nil {
+					return rollback(resp.ID, err)
+				}
+			}
+		}
+
+		if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+			return rollback(resp.ID, err)
+		}
+		// A container that exits straight away, or is restarting after
+		// doing so under its restart policy, failed to start too
+		time.Sleep(recreateGracePeriod)
+		if info, err := cli.ContainerInspect(ctx, resp.ID); err == nil && info.ContainerJSONBase != nil && info.State != nil {
+			switch {
+			case info.State.Restarting || info.RestartCount > 0:
+				return rollback(resp.ID, fmt.Errorf("new container is restarting (exit code %d)", info.State.ExitCode))
+			case !info.State.Running && info.State.ExitCode != 0:
+				return rollback(resp.ID, fmt.Errorf("new container exited with code %d", info.State.ExitCode))
+			}
+		}
+		return runResultMsg{true, fmt.Sprintf("Recreated %s; the previous container is kept as %s", name, backup)}
+	}
+}
+
+// runContainer creates and starts a container from the spec
+func (m Model) runContainer(spec runSpec) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		config, hostConfig, err := spec.containerConfig()
+		if err != nil {
+			return runResultMsg{false, fmt.Sprintf("Error: %v", err)}
+		}
+		resp, err := cli.ContainerCreate(ctx, config, hostConfig, nil, nil, spec.Name)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return runResultMsg{false, fmt.Sprintf("Image %s not found locally - press ctrl+p to pull it", spec.Image)}
+			}
+			return runResultMsg{false, fmt.Sprintf("Failed to create container: %v", err)}
+		}
+		name := spec.Name
+		if name == "" {
+			name = shortID(resp.ID)
+		}
+		if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+			return runResultMsg{true, fmt.Sprintf("Created %s but failed to start it: %v", name, err)}
+		}
+		return runResultMsg{true, fmt.Sprintf("Started %s", name)}
+	}
+}
+
+// handleRunKey handles keys in the run form
+func (m *Model) handleRunKey(msg tea.KeyMsg) tea.Cmd {
+	if m.runSubmitting {
+		return nil
+	}
+	if m.runConfirming {
+		switch msg.String() {
+		case "y", "Y":
+			m.runConfirming = false
+			spec, err := parseRunForm(m.runForm)
+			if err != nil {
+				m.statusMsg = fmt.Sprintf("Error: %v", err)
+				return nil
+			}
+			m.runSubmitting = true
+			m.statusMsg = fmt.Sprintf("Recreating %s...", m.runRecreate.Name)
+			return m.recreateContainer(*m.runRecreate, spec)
+		case "n", "N", "esc":
+			m.runConfirming = false
+			m.statusMsg = "Recreate cancelled"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		// Ignore other keys during confirmation
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.currentView = m.runReturn
+		m.statusMsg = ""
+	case "tab", "down":
+		m.runFocus = (m.runFocus + 1) % runFieldCount
+	case "shift+tab", "up":
+		m.runFocus = (m.runFocus + runFieldCount - 1) % runFieldCount
+	case "ctrl+p":
+		// Pull the image first, coming back to the form afterwards
+		m.openPull(strings.TrimSpace(m.runForm[runImage]))
+	case "enter":
+		spec, err := parseRunForm(m.runForm)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", err)
+			return nil
+		}
+		if conflicts := m.runPortConflicts(spec); len(conflicts) > 0 {
+			m.statusMsg = "Error: " + strings.Join(conflicts, "; ")
+			return nil
+		}
+		if m.runRecreate != nil {
+			m.runConfirming = true
+			m.statusMsg = fmt.Sprintf("⚠️  Recreate '%s' with this configuration? The current container is kept as a backup [y/n]", m.runRecreate.Name)
+			return nil
+		}
+		m.runSubmitting = true
+		m.statusMsg = "Creating container..."
+		return m.runContainer(spec)
+	case "backspace":
+		value := m.runForm[m.runFocus]
+		if len(value) > 0 {
+			_, size := utf8.DecodeLastRuneInString(value)
+			m.runForm[m.runFocus] = value[:len(value)-size]
+		}
+	case "ctrl+u":
+		m.runForm[m.runFocus] = ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.runForm[m.runFocus] += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// copyStage is the step of the copy prompt that is open
+type copyStage int
+
+const (
+	copyStageNone          copyStage = iota
+	copyStageDirection               // Choosing out of or into the container
+	copyStageContainerPath           // Typing the path in the container
+	copyStageLocalPath               // Typing the local path
+)
+
+// copyStatMsg is sent when a container path has been checked with ContainerStatPath
+type copyStatMsg struct {
+	path string
+	stat container.PathStat
+	err  error
+}
+
+// copyProgressMsg reports bytes copied so far
+type copyProgressMsg struct {
+	id    int
+	done  int64
+	total int64 // 0 when unknown
+}
+
+// copyDoneMsg is sent when a copy finishes
+type copyDoneMsg struct {
+	id      int
+	err     error
+	message string
+}
+
+// copyProgressInterval limits how often progress reaches the status bar
+const copyProgressInterval = 100 * time.Millisecond
+
+// copyReporter counts bytes and sends throttled progress messages
+type copyReporter struct {
+	id    int
+	ch    chan<- tea.Msg
+	ctx   context.Context
+	total int64
+	done  int64
+	last  time.Time
+}
+
+// add records n more bytes copied
+func (r *copyReporter) add(n int64) {
+	r.done += n
+	if time.Since(r.last) < copyProgressInterval {
+		return
+	}
+	r.last = time.Now()
+	select {
+	case r.ch <- copyProgressMsg{id: r.id, done: r.done, total: r.total}:
+	case <-r.ctx.Done():
+	}
+}
+
+// progressWriter passes writes through, reporting how many bytes went by
+type progressWriter struct {
+	w   io.Writer
+	add func(int64)
+}
+
+func (p progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.add(int64(n))
+	return n, err
+}
+
+// extractTar unpacks a tar stream into dest and returns how many files it
+// wrote. Everything is written through an os.Root, so entries can't land
+// outside dest by name or by way of a symlink extracted earlier.
+func extractTar(r io.Reader, dest string, add func(int64)) (int, error) {
+	root, err := os.OpenRoot(dest)
+	if err != nil {
+		return 0, err
+	}
+	defer root.Close()
+
+	files := 0
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+
+		name := filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return files, fmt.Errorf("refusing to extract %q outside %s", hdr.Name, dest)
+		}
+		if dir := filepath.Dir(name); dir != "." {
+			if err := root.MkdirAll(dir, 0o755); err != nil {
+				return files, err
+			}
+		}
+
+		mode := hdr.FileInfo().Mode().Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := root.MkdirAll(name, mode|0o700); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			f, err := root.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+			if err != nil {
+				return files, err
+			}
+			_, err = io.Copy(progressWriter{f, add}, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return files, err
+			}
+			files++
+		case tar.TypeSymlink:
+			// The link itself may point anywhere; the root refuses to
+			// follow it out of dest when later entries are written
+			_ = root.Remove(name)
+			if err := root.Symlink(hdr.Linkname, name); err != nil {
+				return files, err
+			}
+			continue
+		case tar.TypeLink:
+			source := filepath.FromSlash(hdr.Linkname)
+			if !filepath.IsLocal(source) {
+				return files, fmt.Errorf("refusing to link %q outside %s", hdr.Linkname, dest)
+			}
+			_ = root.Remove(name)
+			if err := root.Link(source, name); err != nil {
+				return files, err
+			}
+			files++
+		default:
+			// Devices, fifos and the like aren't recreated locally
+			continue
+		}
+		_ = root.Chtimes(name, hdr.ModTime, hdr.ModTime)
+	}
+}
+
+// localSize returns the total size of the regular files under path
+func localSize(path string) (int64, error) {
+	var total int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// writeTar archives a local file or directory, named by its base name, the
+// way `docker cp` sends it: owned by root inside the container
+func writeTar(w io.Writer, src string, add func(int64)) error {
+	tw := tar.NewWriter(w)
+	base := filepath.Dir(src)
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(progressWriter{tw, add}, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// statContainerPath checks a path in the copy target with ContainerStatPath
+func (m Model) statContainerPath(id, path string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		stat, err := cli.ContainerStatPath(ctx, id, path)
+		return copyStatMsg{path: path, stat: stat, err: err}
+	}
+}
+
+// copyOut extracts a container path into a local directory, reporting
+// progress through ch
+func (m Model) copyOut(id int, containerID, src, dest string, total int64, ch chan<- tea.Msg) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		go func() {
+			r := &copyReporter{id: id, ch: ch, ctx: ctx, total: total}
+			done := func(err error, message string) {
+				ch <- copyDoneMsg{id: id, err: err, message: message}
+			}
+
+			if err := os.MkdirAll(dest, 0o755); err != nil {
+				done(err, "")
+				return
+			}
+			body, _, err := cli.CopyFromContainer(ctx, containerID, src)
+			if err != nil {
+				done(err, "")
+				return
+			}
+			defer body.Close()
+			files, err := extractTar(body, dest, r.add)
+			done(err, fmt.Sprintf("(%s, %s)", pluralize(files, "file"), formatBytes(float64(r.done), false)))
+		}()
+		return nil
+	}
+}
+
+// copyIn sends a local file or directory into a container directory,
+// reporting progress through ch
+func (m Model) copyIn(id int, containerID, src, dest string, ch chan<- tea.Msg) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		go func() {
+			done := func(err error, message string) {
+				ch <- copyDoneMsg{id: id, err: err, message: message}
+			}
+			total, err := localSize(src)
+			if err != nil {
+				done(err, "")
+				return
+			}
+			r := &copyReporter{id: id, ch: ch, ctx: ctx, total: total}
+
+			pr, pw := io.Pipe()
+			go func() {
+				pw.CloseWithError(writeTar(pw, src, r.add))
+			}()
+			err = cli.CopyToContainer(ctx, containerID, dest, pr, container.CopyToContainerOptions{})
+			pr.CloseWithError(err)
+			done(err, fmt.Sprintf("(%s)", formatBytes(float64(r.done), false)))
+		}()
+		return nil
+	}
+}
+
+// waitForCopy returns a command that blocks until the next copy message arrives
+func waitForCopy(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// openCopyPrompt starts the copy prompt for the container under the cursor
+func (m *Model) openCopyPrompt() {
+	if m.copyRunning {
+		m.statusMsg = "A copy is already in progress"
+		return
+	}
+	if len(m.containers) == 0 {
+		m.statusMsg = "No container selected"
+		return
+	}
+	m.copyTarget = m.containers[m.cursor]
+	m.copyStage = copyStageDirection
+}
+
+// startCopy begins copying between the validated container path and a local path
+func (m *Model) startCopy(local string) tea.Cmd {
+	m.copyID++
+	m.copyCh = make(chan tea.Msg)
+	m.copyRunning = true
+	m.copyStage = copyStageNone
+
+	var cmd tea.Cmd
+	if m.copyInto {
+		m.copyLabel = fmt.Sprintf("%s → %s:%s", local, m.copyTarget.Name, m.copyContainerPath)
+		cmd = m.copyIn(m.copyID, m.copyTarget.ID, local, m.copyContainerPath, m.copyCh)
+	} else {
+		m.copyLabel = fmt.Sprintf("%s:%s → %s", m.copyTarget.Name, m.copyContainerPath, local)
+		var total int64
+		if m.copyStat.Mode.IsRegular() {
+			total = m.copyStat.Size
+		}
+		cmd = m.copyOut(m.copyID, m.copyTarget.ID, m.copyContainerPath, local, total, m.copyCh)
+	}
+	m.statusMsg = "Copying " + m.copyLabel + "..."
+	return tea.Batch(cmd, waitForCopy(m.copyCh))
+}
+
+// copyPromptText returns the label of the open copy prompt
+func (m Model) copyPromptText() string {
+	switch m.copyStage {
+	case copyStageDirection:
+		return fmt.Sprintf("Copy files for %s: [o] out of the container  [i] into the container", m.copyTarget.Name)
+	case copyStageContainerPath:
+		if m.copyInto {
+			return fmt.Sprintf("Copy into %s, destination directory in the container: ", m.copyTarget.Name)
+		}
+		return fmt.Sprintf("Copy out of %s, path in the container: ", m.copyTarget.Name)
+	case copyStageLocalPath:
+		if m.copyInto {
+			return fmt.Sprintf("Local file or directory to copy into %s:%s: ", m.copyTarget.Name, m.copyContainerPath)
+		}
+		return fmt.Sprintf("Extract %s:%s into local directory: ", m.copyTarget.Name, m.copyContainerPath)
+	}
+	return ""
+}
+
+// handleCopyKey handles keys while the copy prompt is open
+func (m *Model) handleCopyKey(msg tea.KeyMsg) tea.Cmd {
+	if m.copyStage == copyStageDirection {
+		switch msg.String() {
+		case "o", "i":
+			m.copyInto = msg.String() == "i"
+			m.copyStage = copyStageContainerPath
+			m.copyInput = "/"
+		case "esc", "q":
+			m.copyStage = copyStageNone
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.copyStage = copyStageNone
+	case "enter":
+		input := strings.TrimSpace(m.copyInput)
+		if input == "" {
+			return nil
+		}
+		if m.copyStage == copyStageContainerPath {
+			m.statusMsg = "Checking " + input + "..."
+			return m.statContainerPath(m.copyTarget.ID, input)
+		}
+		if rest, ok := strings.CutPrefix(input, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				input = filepath.Join(home, rest)
+			}
+		}
+		if m.copyInto {
+			if _, err := os.Stat(input); err != nil {
+				m.statusMsg = fmt.Sprintf("Error: %v", err)
+				return nil
+			}
+		}
+		return m.startCopy(input)
+	case "backspace":
+		if len(m.copyInput) > 0 {
+			m.copyInput = m.copyInput[:len(m.copyInput)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.copyInput += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// fileEntry is one row of the in-container file browser
+type fileEntry struct {
+	Name       string
+	Mode       os.FileMode
+	Size       int64
+	Mtime      time.Time
+	LinkTarget string
+}
+
+// filesListedMsg is sent when a container directory has been listed
+type filesListedMsg struct {
+	id      int
+	path    string
+	entries []fileEntry
+	err     error
+}
+
+// filesPreviewMsg is sent when a container file has been read for preview
+type filesPreviewMsg struct {
+	id    int
+	path  string
+	lines []string
+	note  string // Shown instead of the contents, e.g. for binary files
+	err   error
+}
+
+// filesPreviewLimit is how much of a file the preview reads
+const filesPreviewLimit = 64 * 1024
+
+// unixFileMode converts a raw st_mode, as printed by stat -c %f, to an os.FileMode
+func unixFileMode(raw uint32) os.FileMode {
+	mode := os.FileMode(raw & 0o777)
+	switch raw & 0o170000 {
+	case 0o040000:
+		mode |= os.ModeDir
+	case 0o120000:
+		mode |= os.ModeSymlink
+	case 0o010000:
+		mode |= os.ModeNamedPipe
+	case 0o140000:
+		mode |= os.ModeSocket
+	case 0o020000:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case 0o060000:
+		mode |= os.ModeDevice
+	}
+	return mode
+}
+
+// parseStatListing parses "mode|size|mtime|name" lines printed by stat -c
+func parseStatListing(output string) []fileEntry {
+	var entries []fileEntry
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) != 4 || fields[3] == "." || fields[3] == ".." {
+			continue
+		}
+		raw, err := strconv.ParseUint(fields[0], 16, 32)
+		if err != nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[1], 10, 64)
+		mtime, _ := strconv.ParseInt(fields[2], 10, 64)
+		entries = append(entries, fileEntry{
+			Name:  fields[3],
+			Mode:  unixFileMode(uint32(raw)),
+			Size:  size,
+			Mtime: time.Unix(mtime, 0),
+		})
+	}
+	return entries
+}
+
+// tarListing returns the direct children of the directory archived in a
+// CopyFromContainer stream, skipping over the contents
+func tarListing(r io.Reader) ([]fileEntry, error) {
+	var entries []fileEntry
+	root := ""
+	tr := tar.NewReader(r)
+	for first := true; ; first = false {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		// The first entry is the directory itself
+		if first {
+			root = name
+			continue
+		}
+		if path.Dir(name) != root {
+			continue
+		}
+		entries = append(entries, fileEntry{
+			Name:       path.Base(name),
+			Mode:       hdr.FileInfo().Mode(),
+			Size:       hdr.Size,
+			Mtime:      hdr.ModTime,
+			LinkTarget: hdr.Linkname,
+		})
+	}
+}
+
+// sortFileEntries puts directories first, then sorts by name
+func sortFileEntries(entries []fileEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Mode.IsDir() != entries[j].Mode.IsDir() {
+			return entries[i].Mode.IsDir()
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// execListing lists a directory in a running container with stat, which
+// avoids transferring the directory's contents
+func execListing(ctx context.Context, cli *client.Client, id, dir string) ([]fileEntry, error) {
+	stdout, _, _, err := runExec(ctx, cli, id, []string{"/bin/sh", "-c", `cd "$1" && stat -c '%f|%s|%Y|%n' -- .* * 2>/dev/null`, "sh", dir})
+	if err != nil {
+		return nil, err
+	}
+	// An unmatched * makes stat fail, so the exit code isn't checked; an
+	// empty directory still lists . and .. from .*
+	if stdout == "" {
+		return nil, errors.New("stat printed nothing")
+	}
+	return parseStatListing(stdout), nil
+}
+
+// loadFiles lists a directory of the browsed container. Running containers
+// are listed with stat over exec; stopped ones, or images without a shell,
+// fall back to reading the tar headers from CopyFromContainer.
+func (m Model) loadFiles(dir string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	id, c := m.filesID, m.filesContainer
+	return func() tea.Msg {
+		stat, err := cli.ContainerStatPath(ctx, c.ID, dir)
+		if err != nil {
+			return filesListedMsg{id: id, path: dir, err: err}
+		}
+		if !stat.Mode.IsDir() {
+			return filesListedMsg{id: id, path: dir, err: fmt.Errorf("%s is not a directory", dir)}
+		}
+
+		if c.State == "running" {
+			if entries, err := execListing(ctx, cli, c.ID, dir); err == nil {
+				sortFileEntries(entries)
+				return filesListedMsg{id: id, path: dir, entries: entries}
+			}
+		}
+
+		body, _, err := cli.CopyFromContainer(ctx, c.ID, dir)
+		if err != nil {
+			return filesListedMsg{id: id, path: dir, err: err}
+		}
+		defer body.Close()
+		entries, err := tarListing(body)
+		sortFileEntries(entries)
+		return filesListedMsg{id: id, path: dir, entries: entries, err: err}
+	}
+}
+
+// previewLines splits file contents into lines for the preview, or explains
+// why they can't be shown
+func previewLines(data []byte, size int64) ([]string, string) {
+	text := data
+	if size > int64(len(data)) {
+		// The read may have stopped partway through a character
+		for i := 0; i < utf8.UTFMax && len(text) > 0 && !utf8.Valid(text); i++ {
+			text = text[:len(text)-1]
+		}
+	}
+	if bytes.IndexByte(text, 0) >= 0 || !utf8.Valid(text) {
+		return nil, fmt.Sprintf("Binary file (%s), press d to download it", formatBytes(float64(size), false))
+	}
+
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSuffix(string(text), "\n"), "\t", "    "), "\n")
+	note := ""
+	if size > int64(len(data)) {
+		note = fmt.Sprintf("Showing the first %s of %s", formatBytes(float64(len(data)), false), formatBytes(float64(size), false))
+	}
+	return lines, note
+}
+
+// previewFile reads the start of a container file with CopyFromContainer
+func (m Model) previewFile(file string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	id, containerID := m.filesID, m.filesContainer.ID
+	return func() tea.Msg {
+		body, _, err := cli.CopyFromContainer(ctx, containerID, file)
+		if err != nil {
+			return filesPreviewMsg{id: id, path: file, err: err}
+		}
+		defer body.Close()
+
+		tr := tar.NewReader(body)
+		hdr, err := tr.Next()
+		if err != nil {
+			return filesPreviewMsg{id: id, path: file, err: err}
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return filesPreviewMsg{id: id, path: file, note: "Not a regular file"}
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, filesPreviewLimit))
+		if err != nil {
+			return filesPreviewMsg{id: id, path: file, err: err}
+		}
+		lines, note := previewLines(data, hdr.Size)
+		return filesPreviewMsg{id: id, path: file, lines: lines, note: note}
+	}
+}
+
+// openFiles switches to the file browser for the container under the cursor
+func (m *Model) openFiles() tea.Cmd {
+	if len(m.containers) == 0 {
+		m.statusMsg = "No container selected"
+		return nil
+	}
+	m.filesContainer = m.containers[m.cursor]
+	m.filesPath = "/"
+	m.filesEntries = nil
+	m.filesCursor = 0
+	m.filesPreviewing = false
+	m.filesLoading = true
+	m.filesID++
+	m.currentView = viewFiles
+	m.statusMsg = ""
+	return m.loadFiles("/")
+}
+
+// selectedFile returns the entry under the file browser cursor
+func (m Model) selectedFile() (fileEntry, bool) {
+	if m.filesCursor >= len(m.filesEntries) {
+		return fileEntry{}, false
+	}
+	return m.filesEntries[m.filesCursor], true
+}
+
+// openFileEntry enters a directory or previews a file. Symlinks are
+// resolved with ContainerStatPath to decide which.
+func (m *Model) openFileEntry(entry fileEntry) tea.Cmd {
+	target := path.Join(m.filesPath, entry.Name)
+	m.filesID++
+	switch {
+	case entry.Mode.IsDir():
+		m.filesLoading = true
+		return m.loadFiles(target)
+	case entry.Mode&os.ModeSymlink != 0:
+		cli, ctx := m.dockerClient, m.ctx
+		id, containerID := m.filesID, m.filesContainer.ID
+		files := *m
+		return func() tea.Msg {
+			stat, err := cli.ContainerStatPath(ctx, containerID, target)
+			if err != nil {
+				return filesPreviewMsg{id: id, path: target, err: err}
+			}
+			// Copying a symlink copies the link itself, so its resolved
+			// target is opened instead
+			resolved := target
+			if stat.LinkTarget != "" {
+				resolved = stat.LinkTarget
+				if stat, err = cli.ContainerStatPath(ctx, containerID, resolved); err != nil {
+					return filesPreviewMsg{id: id, path: target, err: err}
+				}
+			}
+			if stat.Mode.IsDir() {
+				return files.loadFiles(resolved)()
+			}
+			return files.previewFile(resolved)()
+		}
+	default:
+		m.statusMsg = "Loading " + entry.Name + "..."
+		return m.previewFile(target)
+	}
+}
+
+// startFilesCopy opens the local path prompt of the copy flow for the file
+// browser: downloading the selected entry, or uploading into the current directory
+func (m *Model) startFilesCopy(into bool) {
+	if m.copyRunning {
+		m.statusMsg = "A copy is already in progress"
+		return
+	}
+	m.copyTarget = m.filesContainer
+	m.copyInto = into
+	m.copyStage = copyStageLocalPath
+	if into {
+		m.copyContainerPath = m.filesPath
+		m.copyInput = ""
+		return
+	}
+	m.copyContainerPath = m.filesPath
+	m.copyStat = container.PathStat{}
+	if m.filesPreviewing {
+		m.copyContainerPath = m.filesPreviewPath
+	} else if entry, ok := m.selectedFile(); ok {
+		m.copyContainerPath = path.Join(m.filesPath, entry.Name)
+		m.copyStat = container.PathStat{Name: entry.Name, Size: entry.Size, Mode: entry.Mode}
+	}
+	m.copyInput = "."
+}
+
+// handleFilesKey handles keys in the file browser
+func (m *Model) handleFilesKey(msg tea.KeyMsg) tea.Cmd {
+	if m.copyStage != copyStageNone {
+		return m.handleCopyKey(msg)
+	}
+
+	if m.filesPreviewing {
+		switch msg.String() {
+		case "esc", "q", "h", "left", "backspace":
+			m.filesPreviewing = false
+		case "up", "k":
+			m.filesPreviewOffset = max(m.filesPreviewOffset-1, 0)
+		case "down", "j":
+			m.filesPreviewOffset = min(m.filesPreviewOffset+1, max(len(m.filesPreviewLines)-1, 0))
+		case "pgup":
+			m.filesPreviewOffset = max(m.filesPreviewOffset-m.filesPageSize(), 0)
+		case "pgdown", " ":
+			m.filesPreviewOffset = min(m.filesPreviewOffset+m.filesPageSize(), max(len(m.filesPreviewLines)-1, 0))
+		case "d":
+			m.startFilesCopy(false)
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = viewList
+		m.statusMsg = ""
+	case "up", "k":
+		if m.filesCursor > 0 {
+			m.filesCursor--
+		}
+	case "down", "j":
+		if m.filesCursor < len(m.filesEntries)-1 {
+			m.filesCursor++
+		}
+	case "enter", "l", "right":
+		if entry, ok := m.selectedFile(); ok {
+			return m.openFileEntry(entry)
+		}
+	case "backspace", "h", "left":
+		if m.filesPath != "/" {
+			m.filesLoading = true
+			m.filesID++
+			return m.loadFiles(path.Dir(m.filesPath))
+		}
+	case "r":
+		m.filesLoading = true
+		m.filesID++
+		return m.loadFiles(m.filesPath)
+	case "d":
+		if _, ok := m.selectedFile(); ok {
+			m.startFilesCopy(false)
+		}
+	case "u":
+		m.startFilesCopy(true)
+	}
+	return nil
+}
+
+// filesPageSize is how many rows the file browser shows at once
+func (m Model) filesPageSize() int {
+	return max(m.height-12, 3)
+}
+
+// diffNode is a path in the container filesystem diff tree
+type diffNode struct {
+	Name     string
+	Path     string
+	Kind     container.ChangeType
+	Changed  bool // Whether ContainerDiff reported this path, rather than only paths below it
+	Children []*diffNode
+	Counts   [3]int // Changes at or below this path, indexed by ChangeType
+}
+
+// diffRow is a visible line of the diff tree
+type diffRow struct {
+	node  *diffNode
+	depth int
+}
+
+// diffLoadedMsg is sent when ContainerDiff returns
+type diffLoadedMsg struct {
+	id   int
+	root *diffNode
+	err  error
+}
+
+// diffCollapseThreshold is how many changes a directory can hold before it
+// starts out collapsed
+const diffCollapseThreshold = 20
+
+// diffKindStyles colour the A/C/D markers
+var diffKindStyles = map[container.ChangeType]lipgloss.Style{
+	container.ChangeAdd:    runningStyle,
+	container.ChangeModify: pausedStyle,
+	container.ChangeDelete: stderrStyle,
+}
+
+// buildDiffTree arranges ContainerDiff changes into a tree rooted at /,
+// with children sorted by name and per-kind counts on every directory
+func buildDiffTree(changes []container.FilesystemChange) *diffNode {
+	root := &diffNode{Name: "/", Path: "/"}
+	nodes := map[string]*diffNode{"/": root}
+	var node func(p string) *diffNode
+	node = func(p string) *diffNode {
+		if n, ok := nodes[p]; ok {
+			return n
+		}
+		parent := node(path.Dir(p))
+		n := &diffNode{Name: path.Base(p), Path: p}
+		parent.Children = append(parent.Children, n)
+		nodes[p] = n
+		return n
+	}
+
+	for _, change := range changes {
+		p := path.Clean("/" + change.Path)
+		if p == "/" {
+			continue
+		}
+		n := node(p)
+		n.Kind = change.Kind
+		n.Changed = true
+		for ; p != "/"; p = path.Dir(p) {
+			nodes[p].Counts[change.Kind]++
+		}
+		root.Counts[change.Kind]++
+	}
+
+	var sortChildren func(n *diffNode)
+	sortChildren = func(n *diffNode) {
+		sort.Slice(n.Children, func(i, j int) bool {
+			return n.Children[i].Name < n.Children[j].Name
+		})
+		for _, child := range n.Children {
+			sortChildren(child)
+		}
+	}
+	sortChildren(root)
+	return root
+}
+
+// total returns the number of changes at or below n
+func (n *diffNode) total() int {
+	return n.Counts[container.ChangeAdd] + n.Counts[container.ChangeModify] + n.Counts[container.ChangeDelete]
+}
+
+// loadDiff fetches the writable layer changes of the diffed container
+func (m Model) loadDiff() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	id, containerID := m.diffID, m.diffContainer.ID
+	return func() tea.Msg {
+		changes, err := cli.ContainerDiff(ctx, containerID)
+		if err != nil {
+			return diffLoadedMsg{id: id, err: err}
+		}
+		return diffLoadedMsg{id: id, root: buildDiffTree(changes)}
+	}
+}
+
+// openDiff switches to the diff view for the container under the cursor
+func (m *Model) openDiff() tea.Cmd {
+	if len(m.containers) == 0 {
+		m.statusMsg = "No container selected"
+		return nil
+	}
+	m.diffContainer = m.containers[m.cursor]
+	m.diffRoot = nil
+	m.diffCursor = 0
+	m.diffLoading = true
+	m.diffID++
+	m.currentView = viewDiff
+	m.statusMsg = ""
+	return m.loadDiff()
+}
+
+// collapseLargeDiffDirs collapses directories holding many changes so the
+// tree fits on screen at first
+func (m *Model) collapseLargeDiffDirs() {
+	m.diffCollapsed = make(map[string]bool)
+	var walk func(n *diffNode)
+	walk = func(n *diffNode) {
+		for _, child := range n.Children {
+			if len(child.Children) > 0 && child.total() > diffCollapseThreshold {
+				m.diffCollapsed[child.Path] = true
+			}
+			walk(child)
+		}
+	}
+	if m.diffRoot != nil {
+		walk(m.diffRoot)
+	}
+}
+
+// diffRows returns the visible lines of the diff tree
+func (m Model) diffRows() []diffRow {
+	var rows []diffRow
+	var walk func(n *diffNode, depth int)
+	walk = func(n *diffNode, depth int) {
+		for _, child := range n.Children {
+			rows = append(rows, diffRow{node: child, depth: depth})
+			if !m.diffCollapsed[child.Path] {
+				walk(child, depth+1)
+			}
+		}
+	}
+	if m.diffRoot != nil {
+		walk(m.diffRoot, 0)
+	}
+	return rows
+}
+
+// setDiffCollapsed collapses or expands every directory
+func (m *Model) setDiffCollapsed(collapsed bool) {
+	m.diffCollapsed = make(map[string]bool)
+	if collapsed {
+		var walk func(n *diffNode)
+		walk = func(n *diffNode) {
+			for _, child := range n.Children {
+				if len(child.Children) > 0 {
+					m.diffCollapsed[child.Path] = true
+					walk(child)
+				}
+			}
+		}
+		walk(m.diffRoot)
+	}
+	m.diffCursor = min(m.diffCursor, max(len(m.diffRows())-1, 0))
+}
+
+// handleDiffKey handles keys in the diff view
+func (m *Model) handleDiffKey(msg tea.KeyMsg) tea.Cmd {
+	rows := m.diffRows()
+	var row diffRow
+	if m.diffCursor < len(rows) {
+		row = rows[m.diffCursor]
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = viewList
+		m.statusMsg = ""
+	case "up", "k":
+		if m.diffCursor > 0 {
+			m.diffCursor--
+		}
+	case "down", "j":
+		if m.diffCursor < len(rows)-1 {
+			m.diffCursor++
+		}
+	case "enter", " ":
+		if row.node != nil && len(row.node.Children) > 0 {
+			m.diffCollapsed[row.node.Path] = !m.diffCollapsed[row.node.Path]
+		}
+	case "right", "l":
+		if row.node != nil {
+			delete(m.diffCollapsed, row.node.Path)
+		}
+	case "left", "h":
+		// Collapse an open directory, otherwise move to the parent
+		if row.node == nil {
+			return nil
+		}
+		if len(row.node.Children) > 0 && !m.diffCollapsed[row.node.Path] {
+			m.diffCollapsed[row.node.Path] = true
+			return nil
+		}
+		for i := m.diffCursor - 1; i >= 0; i-- {
+			if rows[i].depth < row.depth {
+				m.diffCursor = i
+				break
+			}
+		}
+	case "e":
+		m.setDiffCollapsed(false)
+	case "c":
+		m.setDiffCollapsed(true)
+	case "r":
+		m.diffLoading = true
+		m.diffID++
+		return m.loadDiff()
+	}
+	return nil
+}
+
+// topSortKey is the column the process list is sorted by
+type topSortKey int
+
+const (
+	topSortPID topSortKey = iota
+	topSortCPU
+	topSortMem
+)
+
+// topSortNames label the sort keys, and name the ps column each sorts by
+var topSortNames = []string{"PID", "%CPU", "%MEM"}
+
+// defaultTopArgs are the ps arguments the process list starts with; aux
+// includes the %CPU and %MEM columns it sorts by
+const defaultTopArgs = "aux"
+
+// topRefreshInterval is how often the process list is reloaded
+const topRefreshInterval = 2 * time.Second
+
+// topLoadedMsg is sent when ContainerTop returns
+type topLoadedMsg struct {
+	id        int
+	titles    []string
+	processes [][]string
+	err       error
+}
+
+// topTickMsg triggers the next process list refresh
+type topTickMsg struct {
+	id int
+}
+
+// topSignalMsg is sent when a signal has been sent to a process
+type topSignalMsg struct {
+	success bool
+	message string
+}
+
+// topColumn returns the index of a ps column by title, or -1
+func topColumn(titles []string, title string) int {
+	for i, t := range titles {
+		if strings.EqualFold(t, title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortTopProcesses orders processes by the sort key's column, highest
+// first for CPU and memory. Processes keep ps order when the column is missing.
+func sortTopProcesses(titles []string, processes [][]string, key topSortKey) {
+	col := topColumn(titles, topSortNames[key])
+	if col < 0 {
+		return
+	}
+	value := func(p []string) float64 {
+		if col >= len(p) {
+			return 0
+		}
+		v, _ := strconv.ParseFloat(p[col], 64)
+		return v
+	}
+	sort.SliceStable(processes, func(i, j int) bool {
+		if key == topSortPID {
+			return value(processes[i]) < value(processes[j])
+		}
+		return value(processes[i]) > value(processes[j])
+	})
+}
+
+// loadTop fetches the process list of the container in the top view
+func (m Model) loadTop() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	id, containerID, args := m.topID, m.topContainer.ID, strings.Fields(m.topArgs)
+	return func() tea.Msg {
+		top, err := cli.ContainerTop(ctx, containerID, args)
+		if err != nil {
+			return topLoadedMsg{id: id, err: err}
+		}
+		return topLoadedMsg{id: id, titles: top.Titles, processes: top.Processes}
+	}
+}
+
+// topTick schedules the next refresh of the process list
+func topTick(id int) tea.Cmd {
+	return tea.Tick(topRefreshInterval, func(time.Time) tea.Msg {
+		return topTickMsg{id: id}
+	})
+}
+
+// openTop switches to the process list for the container under the cursor
+func (m *Model) openTop() tea.Cmd {
+	if len(m.containers) == 0 {
+		m.statusMsg = "No container selected"
+		return nil
+	}
+	m.topContainer = m.containers[m.cursor]
+	if m.topArgs == "" {
+		m.topArgs = defaultTopArgs
+	}
+	m.topTitles, m.topProcesses = nil, nil
+	m.topCursor, m.topPID = 0, ""
+	m.topID++
+	m.topLoading = true
+	m.currentView = viewTop
+	m.statusMsg = ""
+	return m.loadTop()
+}
+
+// closeTop leaves the process list; pending refreshes are dropped by the ID change
+func (m *Model) closeTop() {
+	m.topID++
+	m.topSignalPicker = false
+	m.topArgsPrompt = false
+	m.currentView = viewList
+	m.statusMsg = ""
+}
+
+// procEntry is a process as seen from inside a container
+type procEntry struct {
+	PID     string
+	Elapsed float64 // Seconds since the process started
+	Command string  // Arguments joined with spaces, as ps prints them
+}
+
+// procListScript prints the uptime, then pid|stat fields after the
+// command name|command line for every process visible in the container
+const procListScript = `read up _ < /proc/uptime; echo "$up"
+for d in /proc/[0-9]*; do
+	s=$(cat "$d/stat" 2>/dev/null) || continue
+	echo "${d#/proc/}|${s##*) }|$(tr '\0' ' ' < "$d/cmdline" 2>/dev/null)"
+done`
+
+// clockTicks is USER_HZ, the unit of the starttime field in /proc/<pid>/stat
+const clockTicks = 100
+
+// parseProcListing parses the output of procListScript
+func parseProcListing(output string) []procEntry {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	uptime, err := strconv.ParseFloat(strings.TrimSpace(lines[0]), 64)
+	if err != nil {
+		return nil
+	}
+	var procs []procEntry
+	for _, line := range lines[1:] {
+		fields := strings.SplitN(line, "|", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// starttime is field 22 of stat, the 20th after the command name
+		stat := strings.Fields(fields[1])
+		if len(stat) < 20 {
+			continue
+		}
+		start, err := strconv.ParseFloat(stat[19], 64)
+		if err != nil {
+			continue
+		}
+		procs = append(procs, procEntry{
+			PID:     fields[0],
+			Elapsed: uptime - start/clockTicks,
+			Command: strings.TrimSpace(fields[2]),
+		})
+	}
+	return procs
+}
+
+// matchContainerPID finds the process inside the container that ps on the
+// host reported with the given command line and elapsed seconds (-1 when
+// unknown). It fails rather than guess when no single process matches.
+func matchContainerPID(procs []procEntry, command string, elapsed int) (string, error) {
+	// ps collapses runs of spaces in the arguments, so compare word by word
+	normalize := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	command = normalize(command)
+	var matches []procEntry
+	for _, p := range procs {
+		if command == "" || normalize(p.Command) != command {
+			continue
+		}
+		if elapsed >= 0 && math.Abs(p.Elapsed-float64(elapsed)) > 2 {
+			continue
+		}
+		matches = append(matches, p)
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no process running %q found in the container", command)
+	case 1:
+		return matches[0].PID, nil
+	default:
+		return "", fmt.Errorf("%d processes running %q started at the same time", len(matches), command)
+	}
+}
+
+// resolveContainerPID translates a host PID, as ContainerTop reports it, to
+// the PID inside the container's namespace. The host's /proc may not be
+// reachable (Docker Desktop, a remote daemon, lcm in a container), so the
+// process is looked up inside the container by command line and start time.
+func resolveContainerPID(ctx context.Context, cli *client.Client, id, hostPID string) (string, error) {
+	top, err := cli.ContainerTop(ctx, id, []string{"-eo", "pid,etimes,args"})
+	if err != nil {
+		return "", err
+	}
+	command, elapsed := "", -1
+	for _, p := range top.Processes {
+		if len(p) >= 3 && p[0] == hostPID {
+			elapsed, _ = strconv.Atoi(p[1])
+			command = strings.Join(p[2:], " ")
+		}
+	}
+	if command == "" {
+		return "", fmt.Errorf("PID %s is no longer running", hostPID)
+	}
+
+	stdout, stderr, code, err := runExec(ctx, cli, id, []string{"/bin/sh", "-c", procListScript})
+	if err == nil && code != 0 {
+		err = errors.New(strings.TrimSpace(stderr))
+	}
+	if err != nil {
+		return "", fmt.Errorf("listing processes in the container: %w", err)
+	}
+	return matchContainerPID(parseProcListing(stdout), command, elapsed)
+}
+
+// runExec runs a command in a container and returns its output and exit code
+func runExec(ctx context.Context, cli *client.Client, id string, cmd []string) (string, string, int, error) {
+	execResp, err := cli.ContainerExecCreate(ctx, id, container.ExecOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return "", "", 0, err
+	}
+	attach, err := cli.ContainerExecAttach(ctx, execResp.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer attach.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil {
+		return "", "", 0, err
+	}
+	inspect, err := cli.ContainerExecInspect(ctx, execResp.ID)
+	if err != nil {
+		return stdout.String(), stderr.String(), 0, err
+	}
+	return stdout.String(), stderr.String(), inspect.ExitCode, nil
+}
+
+// signalProcess sends a signal to a process by running kill inside the container
+func (m Model) signalProcess(hostPID, signal string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	c := m.topContainer
+	return func() tea.Msg {
+		pid, err := resolveContainerPID(ctx, cli, c.ID, hostPID)
+		if err != nil {
+			return topSignalMsg{false, fmt.Sprintf("Not sending %s: can't find host PID %s inside %s: %v", signal, hostPID, c.Name, err)}
+		}
+		_, stderr, code, err := runExec(ctx, cli, c.ID, []string{"/bin/sh", "-c", `kill -s "$1" "$2"`, "sh", strings.TrimPrefix(signal, "SIG"), pid})
+		if err == nil && code != 0 {
+			err = errors.New(strings.TrimSpace(stderr))
+		}
+		if err != nil {
+			return topSignalMsg{false, fmt.Sprintf("Failed to send %s to PID %s: %v", signal, pid, err)}
+		}
+		return topSignalMsg{true, fmt.Sprintf("Sent %s to PID %s in %s (host PID %s)", signal, pid, c.Name, hostPID)}
+	}
+}
+
+// selectedTopPID returns the PID of the process under the cursor
+func (m Model) selectedTopPID() string {
+	col := topColumn(m.topTitles, "PID")
+	if col < 0 || m.topCursor >= len(m.topProcesses) || col >= len(m.topProcesses[m.topCursor]) {
+		return ""
+	}
+	return m.topProcesses[m.topCursor][col]
+}
+
+// handleTopKey handles keys in the process list
+func (m *Model) handleTopKey(msg tea.KeyMsg) tea.Cmd {
+	if m.topSignalPicker {
+		switch msg.String() {
+		case "esc", "q":
+			m.topSignalPicker = false
+		case "left", "h":
+			m.topSignal = (m.topSignal + len(killSignals) - 1) % len(killSignals)
+		case "right", "l", "tab":
+			m.topSignal = (m.topSignal + 1) % len(killSignals)
+		case "enter":
+			m.topSignalPicker = false
+			signal := killSignals[m.topSignal]
+			m.statusMsg = fmt.Sprintf("Sending %s to PID %s...", signal, m.topSignalPID)
+			return m.signalProcess(m.topSignalPID, signal)
+		default:
+			if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(killSignals) {
+				m.topSignal = n - 1
+			}
+		}
+		return nil
+	}
+
+	if m.topArgsPrompt {
+		switch msg.String() {
+		case "esc":
+			m.topArgsPrompt = false
+		case "enter":
+			m.topArgsPrompt = false
+			m.topArgs = strings.TrimSpace(m.topArgsInput)
+			if m.topArgs == "" {
+				m.topArgs = defaultTopArgs
+			}
+			// Restart the refresh cycle with the new arguments
+			m.topID++
+			m.topLoading = true
+			return m.loadTop()
+		case "backspace":
+			if len(m.topArgsInput) > 0 {
+				m.topArgsInput = m.topArgsInput[:len(m.topArgsInput)-1]
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				m.topArgsInput += string(msg.Runes)
+			}
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTop()
+	case "up", "k":
+		if m.topCursor > 0 {
+			m.topCursor--
+		}
+		m.topPID = m.selectedTopPID()
+	case "down", "j":
+		if m.topCursor < len(m.topProcesses)-1 {
+			m.topCursor++
+		}
+		m.topPID = m.selectedTopPID()
+	case "s":
+		m.topSort = (m.topSort + 1) % topSortKey(len(topSortNames))
+		if topColumn(m.topTitles, topSortNames[m.topSort]) < 0 && len(m.topTitles) > 0 {
+			m.statusMsg = fmt.Sprintf("No %s column; add it to the ps arguments with a", topSortNames[m.topSort])
+		} else {
+			m.statusMsg = ""
+		}
+		sortTopProcesses(m.topTitles, m.topProcesses, m.topSort)
+		m.followTopPID()
+	case "a":
+		m.topArgsPrompt = true
+		m.topArgsInput = m.topArgs
+	case "K":
+		if pid := m.selectedTopPID(); pid != "" {
+			m.topSignalPicker = true
+			m.topSignalPID = pid
+			m.topSignal = slices.Index(killSignals, "SIGTERM")
+		}
+	case "r":
+		// A new refresh cycle, so the pending tick doesn't start a second one
+		m.topID++
+		m.topLoading = true
+		return m.loadTop()
+	}
+	return nil
+}
+
+// followTopPID keeps the cursor on the selected process after a refresh or re-sort
+func (m *Model) followTopPID() {
+	col := topColumn(m.topTitles, "PID")
+	for i, p := range m.topProcesses {
+		if m.topPID != "" && col >= 0 && col < len(p) && p[col] == m.topPID {
+			m.topCursor = i
+			return
+		}
+	}
+	m.topCursor = min(m.topCursor, max(len(m.topProcesses)-1, 0))
+	m.topPID = m.selectedTopPID()
+}
+
+// formatAge renders how long ago t was, like the docker CLI ("3 days ago")
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "Less than a minute ago"
+	case d < time.Hour:
+		return pluralize(int(d.Minutes()), "minute") + " ago"
+	case d < 48*time.Hour:
+		return pluralize(int(d.Hours()), "hour") + " ago"
+	case d < 14*24*time.Hour:
+		return pluralize(int(d.Hours()/24), "day") + " ago"
+	case d < 60*24*time.Hour:
+		return pluralize(int(d.Hours()/24/7), "week") + " ago"
+	case d < 2*365*24*time.Hour:
+		return pluralize(int(d.Hours()/24/30), "month") + " ago"
+	default:
+		return pluralize(int(d.Hours()/24/365), "year") + " ago"
+	}
+}
+
+// pluralize returns "1 day" or "n days"
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// volumeInfo holds display information about a volume
+type volumeInfo struct {
+	Name       string
+	Driver     string
+	Mountpoint string
+	Size       int64    // Bytes, or -1 until known
+	Containers []string // Names of containers mounting the volume
+}
+
+// volumesLoadedMsg is sent when volumes are loaded from Docker
+type volumesLoadedMsg struct {
+	volumes []volumeInfo
+	err     error
+}
+
+// volumeSizesMsg carries volume sizes from DiskUsage, which can be slow
+// so it is fetched separately from the list
+type volumeSizesMsg struct {
+	sizes map[string]int64
+	err   error
+}
+
+// volumeOperationMsg is sent when a volume operation completes
+type volumeOperationMsg struct {
+	success bool
+	message string
+}
+
+// volumeUsers returns the names of containers mounting each volume
+func volumeUsers(containers []containerInfo) map[string][]string {
+	users := make(map[string][]string)
+	for _, c := range containers {
+		for _, name := range c.Volumes {
+			users[name] = append(users[name], c.Name)
+		}
+	}
+	return users
+}
+
+// loadVolumes fetches volumes from Docker API
+func (m Model) loadVolumes() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	users := volumeUsers(m.allContainers)
+	return func() tea.Msg {
+		resp, err := cli.VolumeList(ctx, volume.ListOptions{})
+		if err != nil {
+			return volumesLoadedMsg{err: err}
+		}
+
+		var volumes []volumeInfo
+		for _, v := range resp.Volumes {
+			volumes = append(volumes, volumeInfo{
+				Name:       v.Name,
+				Driver:     v.Driver,
+				Mountpoint: v.Mountpoint,
+				Size:       -1,
+				Containers: users[v.Name],
+			})
+		}
+		sort.Slice(volumes, func(i, j int) bool {
+			return volumes[i].Name < volumes[j].Name
+		})
+		return volumesLoadedMsg{volumes: volumes}
+	}
+}
+
+// loadVolumeSizes fetches volume sizes from the DiskUsage API
+func (m Model) loadVolumeSizes() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		usage, err := cli.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+		if err != nil {
+			return volumeSizesMsg{err: err}
+		}
+		sizes := make(map[string]int64)
+		for _, v := range usage.Volumes {
+			if v.UsageData != nil {
+				sizes[v.Name] = v.UsageData.Size
+			}
+		}
+		return volumeSizesMsg{sizes: sizes}
+	}
+}
+
+// openVolumes switches to the volumes view and loads the volume list
+func (m *Model) openVolumes() tea.Cmd {
+	m.currentView = viewVolumes
+	m.volumesLoading = true
+	m.statusMsg = ""
+	return tea.Batch(m.loadVolumes(), m.loadVolumeSizes())
+}
+
+// applyVolumeSizes copies the DiskUsage sizes onto the volume rows
+func (m *Model) applyVolumeSizes() {
+	for i := range m.volumes {
+		if size, ok := m.volumeSizes[m.volumes[i].Name]; ok {
+			m.volumes[i].Size = size
+		}
+	}
+}
+
+// unusedVolumes returns the volumes no container mounts
+func (m Model) unusedVolumes() []volumeInfo {
+	var unused []volumeInfo
+	for _, v := range m.volumes {
+		if len(v.Containers) == 0 {
+			unused = append(unused, v)
+		}
+	}
+	return unused
+}
+
+// removeVolume removes the volume confirmed for removal
+func (m Model) removeVolume() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	name := m.volumeToRemove
+	return func() tea.Msg {
+		if err := cli.VolumeRemove(ctx, name, false); err != nil {
+			return volumeOperationMsg{false, fmt.Sprintf("Failed to remove volume: %v", err)}
+		}
+		return volumeOperationMsg{true, fmt.Sprintf("Removed volume %s", name)}
+	}
+}
+
+// pruneVolumes removes every volume not used by a container, named or anonymous
+func (m Model) pruneVolumes() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		report, err := cli.VolumesPrune(ctx, filters.NewArgs(filters.Arg("all", "true")))
+		if err != nil {
+			return volumeOperationMsg{false, fmt.Sprintf("Failed to prune volumes: %v", err)}
+		}
+		return volumeOperationMsg{true, fmt.Sprintf("Pruned %d volumes, reclaimed %s",
+			len(report.VolumesDeleted), formatBytes(float64(report.SpaceReclaimed), false))}
+	}
+}
+
+// handleVolumesKey handles keys in the volumes view
+func (m *Model) handleVolumesKey(msg tea.KeyMsg) tea.Cmd {
+	// Handle confirmation dialogs for remove and prune
+	if m.confirmingVolumeRemove || m.confirmingVolumePrune {
+		switch msg.String() {
+		case "y", "Y":
+			if m.confirmingVolumePrune {
+				m.confirmingVolumePrune = false
+				m.statusMsg = "Pruning volumes..."
+				return m.pruneVolumes()
+			}
+			m.confirmingVolumeRemove = false
+			m.statusMsg = "Removing volume..."
+			return m.removeVolume()
+		case "n", "N", "esc":
+			m.confirmingVolumeRemove = false
+			m.confirmingVolumePrune = false
+			m.volumeToRemove = ""
+			m.statusMsg = "Cancelled"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		// Ignore other keys during confirmation
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = viewList
+		m.statusMsg = ""
+	case "up", "k":
+		if m.volumesCursor > 0 {
+			m.volumesCursor--
+		}
+	case "down", "j":
+		if m.volumesCursor < len(m.volumes)-1 {
+			m.volumesCursor++
+		}
+	case "r", "f5":
+		m.volumesLoading = true
+		return tea.Batch(m.loadVolumes(), m.loadVolumeSizes())
+	case "d":
+		// Remove volume - show confirmation dialog
+		if m.volumesCursor < len(m.volumes) {
+			v := m.volumes[m.volumesCursor]
+			m.confirmingVolumeRemove = true
+			m.volumeToRemove = v.Name
+			m.statusMsg = fmt.Sprintf("⚠️  Remove volume '%s'? [y/n]", v.Name)
+			if len(v.Containers) > 0 {
+				m.statusMsg = fmt.Sprintf("⚠️  Remove volume '%s' (used by %s)? [y/n]", v.Name, strings.Join(v.Containers, ", "))
+			}
+		}
+	case "P":
+		// Prune unused volumes - show confirmation dialog listing them
+		unused := m.unusedVolumes()
+		if len(unused) == 0 {
+			m.statusMsg = "No unused volumes"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		var names []string
+		for _, v := range unused {
+			names = append(names, v.Name)
+		}
+		m.confirmingVolumePrune = true
+		m.statusMsg = fmt.Sprintf("⚠️  Prune %d unused volumes (%s)? [y/n]", len(unused), strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// networkInfo holds display information about a network
+type networkInfo struct {
+	ID       string // Short network ID
+	Name     string
+	Driver   string
+	Scope    string
+	Subnets  []string
+	Gateways []string
+}
+
+// networksLoadedMsg is sent when networks are loaded from Docker
+type networksLoadedMsg struct {
+	networks []networkInfo
+	err      error
+}
+
+// networkOperationMsg is sent when a network operation completes
+type networkOperationMsg struct {
+	success bool
+	message string
+}
+
+// predefinedNetworks are created by Docker and can't be removed
+var predefinedNetworks = map[string]bool{"bridge": true, "host": true, "none": true}
+
+// newNetworkInfo converts a Docker API network summary to display information
+func newNetworkInfo(n network.Summary) networkInfo {
+	info := networkInfo{
+		ID:     shortID(n.ID),
+		Name:   n.Name,
+		Driver: n.Driver,
+		Scope:  n.Scope,
+	}
+	for _, config := range n.IPAM.Config {
+		if config.Subnet != "" {
+			info.Subnets = append(info.Subnets, config.Subnet)
+		}
+		if config.Gateway != "" {
+			info.Gateways = append(info.Gateways, config.Gateway)
+		}
+	}
+	return info
+}
+
+// loadNetworks fetches networks from Docker API
+func (m Model) loadNetworks() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		summaries, err := cli.NetworkList(ctx, network.ListOptions{})
+		if err != nil {
+			return networksLoadedMsg{err: err}
+		}
+
+		var networks []networkInfo
+		for _, n := range summaries {
+			networks = append(networks, newNetworkInfo(n))
+		}
+		sort.Slice(networks, func(i, j int) bool {
+			return networks[i].Name < networks[j].Name
+		})
+		return networksLoadedMsg{networks: networks}
+	}
+}
+
+// openNetworks switches to the networks view for the container under the cursor
+func (m *Model) openNetworks() tea.Cmd {
+	m.networkContainer = containerInfo{}
+	if len(m.containers) > 0 && m.cursorProject == "" {
+		m.networkContainer = m.containers[m.cursor]
+	}
+	m.currentView = viewNetworks
+	m.networksLoading = true
+	m.statusMsg = ""
+	return m.loadNetworks()
+}
+
+// networkAttachments returns "name (ip)" for every container attached to a network
+func (m Model) networkAttachments(name string) []string {
+	var attached []string
+	for _, c := range m.allContainers {
+		if ip, ok := c.Networks[name]; ok {
+			if ip == "" {
+				ip = "no IP"
+			}
+			attached = append(attached, fmt.Sprintf("%s (%s)", c.Name, ip))
+		}
+	}
+	sort.Strings(attached)
+	return attached
+}
+
+// selectedNetwork returns the network row under the cursor
+func (m Model) selectedNetwork() (networkInfo, bool) {
+	if m.networksCursor >= len(m.networks) {
+		return networkInfo{}, false
+	}
+	return m.networks[m.networksCursor], true
+}
+
+// connectNetwork connects (or disconnects) the view's container to the selected network
+func (m Model) connectNetwork(connect bool) tea.Cmd {
+	n, ok := m.selectedNetwork()
+	c := m.networkContainer
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		if !ok || c.ID == "" {
+			return networkOperationMsg{false, "No container or network selected"}
+		}
+		if connect {
+			if err := cli.NetworkConnect(ctx, n.Name, c.ID, nil); err != nil {
+				return networkOperationMsg{false, fmt.Sprintf("Failed to connect: %v", err)}
+			}
+			return networkOperationMsg{true, fmt.Sprintf("Connected %s to %s", c.Name, n.Name)}
+		}
+		if err := cli.NetworkDisconnect(ctx, n.Name, c.ID, false); err != nil {
+			return networkOperationMsg{false, fmt.Sprintf("Failed to disconnect: %v", err)}
+		}
+		return networkOperationMsg{true, fmt.Sprintf("Disconnected %s from %s", c.Name, n.Name)}
+	}
+}
+
+// createNetwork creates a user-defined bridge network
+func (m Model) createNetwork(name string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		if _, err := cli.NetworkCreate(ctx, name, network.CreateOptions{Driver: "bridge"}); err != nil {
+			return networkOperationMsg{false, fmt.Sprintf("Failed to create network: %v", err)}
+		}
+		return networkOperationMsg{true, fmt.Sprintf("Created network %s", name)}
+	}
+}
+
+// removeNetwork removes the network confirmed for removal
+func (m Model) removeNetwork() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	name := m.networkToRemove
+	return func() tea.Msg {
+		if err := cli.NetworkRemove(ctx, name); err != nil {
+			return networkOperationMsg{false, fmt.Sprintf("Failed to remove network: %v", err)}
+		}
+		return networkOperationMsg{true, fmt.Sprintf("Removed network %s", name)}
+	}
+}
+
+// handleNetworksKey handles keys in the networks view
+func (m *Model) handleNetworksKey(msg tea.KeyMsg) tea.Cmd {
+	if m.confirmingNetworkRemove {
+		switch msg.String() {
+		case "y", "Y":
+			m.confirmingNetworkRemove = false
+			m.statusMsg = "Removing network..."
+			return m.removeNetwork()
+		case "n", "N", "esc":
+			m.confirmingNetworkRemove = false
+			m.networkToRemove = ""
+			m.statusMsg = "Remove cancelled"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		return nil
+	}
+
+	if m.networkCreatePrompt {
+		switch msg.String() {
+		case "esc":
+			m.networkCreatePrompt = false
+		case "enter":
+			m.networkCreatePrompt = false
+			if m.networkCreateInput == "" {
+				return nil
+			}
+			m.statusMsg = "Creating network..."
+			return m.createNetwork(m.networkCreateInput)
+		case "backspace":
+			if len(m.networkCreateInput) > 0 {
+				m.networkCreateInput = m.networkCreateInput[:len(m.networkCreateInput)-1]
+			}
+		default:
+			if msg.Type == tea.KeyRunes {
+				m.networkCreateInput += string(msg.Runes)
+			}
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = viewList
+		m.statusMsg = ""
+	case "up", "k":
+		if m.networksCursor > 0 {
+			m.networksCursor--
+		}
+	case "down", "j":
+		if m.networksCursor < len(m.networks)-1 {
+			m.networksCursor++
+		}
+	case "r", "f5":
+		m.networksLoading = true
+		return m.loadNetworks()
+	case "c", "x":
+		if m.networkContainer.ID == "" {
+			m.statusMsg = "Error: open this view with a container selected to connect or disconnect it"
+			return nil
+		}
+		connect := msg.String() == "c"
+		if connect {
+			m.statusMsg = "Connecting..."
+		} else {
+			m.statusMsg = "Disconnecting..."
+		}
+		return m.connectNetwork(connect)
+	case "n":
+		m.networkCreatePrompt = true
+		m.networkCreateInput = ""
+	case "d":
+		// Remove network - show confirmation dialog
+		if n, ok := m.selectedNetwork(); ok {
+			if predefinedNetworks[n.Name] {
+				m.statusMsg = fmt.Sprintf("Error: %s is a predefined network and can't be removed", n.Name)
+				return clearStatusAfterDelay(3 * time.Second)
+			}
+			m.confirmingNetworkRemove = true
+			m.networkToRemove = n.Name
+			m.statusMsg = fmt.Sprintf("⚠️  Remove network '%s'? [y/n]", n.Name)
+		}
+	}
+	return nil
+}
+
+// diskCategory identifies a row of the disk usage view
+type diskCategory int
+
+const (
+	diskImages diskCategory = iota
+	diskContainers
+	diskVolumes
+	diskBuildCache
+)
+
+// pruneCandidate is one object a targeted prune would delete
+type pruneCandidate struct {
+	Name string
+	Size int64
+}
+
+// diskUsageRow summarises one category of the DiskUsage response, the
+// way `docker system df` does, along with what its prune would delete
+type diskUsageRow struct {
+	Category    diskCategory
+	Type        string // "Images", "Containers", ...
+	Total       int
+	Active      int
+	Size        int64
+	Reclaimable int64
+	Prune       string           // What the targeted prune removes, e.g. "dangling images"
+	Candidates  []pruneCandidate // Exactly what the prune would delete
+}
+
+// candidatesSize returns the combined size of a row's prune candidates
+func (r diskUsageRow) candidatesSize() int64 {
+	var total int64
+	for _, c := range r.Candidates {
+		total += c.Size
+	}
+	return total
+}
+
+// diskUsageMsg is sent when disk usage is loaded from Docker
+type diskUsageMsg struct {
+	rows []diskUsageRow
+	err  error
+}
+
+// diskPruneMsg is sent when a targeted prune completes
+type diskPruneMsg struct {
+	success bool
+	message string
+}
+
+// newDiskUsageRows summarises a DiskUsage response. Reclaimable space is
+// computed like `docker system df`; prune candidates match what the
+// corresponding prune API call removes.
+func newDiskUsageRows(du types.DiskUsage) []diskUsageRow {
+	images := diskUsageRow{Category: diskImages, Type: "Images", Size: du.LayersSize, Prune: "dangling images"}
+	var usedImageSize int64
+	for _, img := range du.Images {
+		if img == nil {
+			continue
+		}
+		images.Total++
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+		if img.Containers > 0 {
+			images.Active++
+			usedImageSize += size
+			continue
+		}
+		if len(img.RepoTags) == 0 || (len(img.RepoTags) == 1 && img.RepoTags[0] == "<none>:<none>") {
+			images.Candidates = append(images.Candidates, pruneCandidate{shortID(strings.TrimPrefix(img.ID, "sha256:")), size})
+		}
+	}
+	images.Reclaimable = max(du.LayersSize-usedImageSize, 0)
+
+	containers := diskUsageRow{Category: diskContainers, Type: "Containers", Prune: "stopped containers"}
+	for _, c := range du.Containers {
+		if c == nil {
+			continue
+		}
+		containers.Total++
+		containers.Size += c.SizeRw
+		switch c.State {
+		case "running", "paused", "restarting":
+			containers.Active++
+		default:
+			containers.Reclaimable += c.SizeRw
+			name := shortID(c.ID)
+			if len(c.Names) > 0 {
+				name = strings.TrimPrefix(c.Names[0], "/")
+			}
+			containers.Candidates = append(containers.Candidates, pruneCandidate{name, c.SizeRw})
+		}
+	}
+
+	volumes := diskUsageRow{Category: diskVolumes, Type: "Local Volumes", Prune: "unused volumes"}
+	for _, v := range du.Volumes {
+		if v == nil {
+			continue
+		}
+		volumes.Total++
+		if v.UsageData == nil {
+			continue
+		}
+		size := max(v.UsageData.Size, 0)
+		volumes.Size += size
+		if v.UsageData.RefCount > 0 {
+			volumes.Active++
+			continue
+		}
+		volumes.Reclaimable += size
+		volumes.Candidates = append(volumes.Candidates, pruneCandidate{v.Name, size})
+	}
+
+	cache := diskUsageRow{Category: diskBuildCache, Type: "Build Cache", Prune: "unused build cache"}
+	for _, r := range du.BuildCache {
+		if r == nil {
+			continue
+		}
+		cache.Total++
+		cache.Size += r.Size
+		if r.InUse {
+			cache.Active++
+			continue
+		}
+		if !r.Shared {
+			cache.Reclaimable += r.Size
+		}
+		name := shortID(r.ID)
+		if r.Description != "" {
+			name += " " + r.Description
+		}
+		cache.Candidates = append(cache.Candidates, pruneCandidate{name, r.Size})
+	}
+
+	return []diskUsageRow{images, containers, volumes, cache}
+}
+
+// loadDiskUsage fetches disk usage from the DiskUsage API
+func (m Model) loadDiskUsage() tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		usage, err := cli.DiskUsage(ctx, types.DiskUsageOptions{})
+		if err != nil {
+			return diskUsageMsg{err: err}
+		}
+		return diskUsageMsg{rows: newDiskUsageRows(usage)}
+	}
+}
+
+// openDiskUsage switches to the disk usage view
+func (m *Model) openDiskUsage() tea.Cmd {
+	m.currentView = viewDiskUsage
+	m.diskLoading = true
+	m.statusMsg = ""
+	return m.loadDiskUsage()
+}
+
+// pruneDisk runs the targeted prune for the selected category
+func (m Model) pruneDisk(category diskCategory) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		var (
+			deleted   int
+			reclaimed uint64
+			err       error
+			what      string
+		)
+		switch category {
+		case diskImages:
+			var report image.PruneReport
+			report, err = cli.ImagesPrune(ctx, filters.NewArgs(filters.Arg("dangling", "true")))
+			deleted, reclaimed, what = len(report.ImagesDeleted), report.SpaceReclaimed, "images"
+		case diskContainers:
+			var report container.PruneReport
+			report, err = cli.ContainersPrune(ctx, filters.NewArgs())
+			deleted, reclaimed, what = len(report.ContainersDeleted), report.SpaceReclaimed, "containers"
+		case diskVolumes:
+			var report volume.PruneReport
+			report, err = cli.VolumesPrune(ctx, filters.NewArgs(filters.Arg("all", "true")))
+			deleted, reclaimed, what = len(report.VolumesDeleted), report.SpaceReclaimed, "volumes"
+		case diskBuildCache:
+			var report *build.CachePruneReport
+			report, err = cli.BuildCachePrune(ctx, build.CachePruneOptions{All: true})
+			if report != nil {
+				deleted, reclaimed = len(report.CachesDeleted), report.SpaceReclaimed
+			}
+			what = "build cache records"
+		}
+		if err != nil {
+			return diskPruneMsg{false, fmt.Sprintf("Failed to prune: %v", err)}
+		}
+		return diskPruneMsg{true, fmt.Sprintf("Pruned %d %s, reclaimed %s", deleted, what, formatBytes(float64(reclaimed), false))}
+	}
+}
+
+// diskPreviewHeight is how many prune candidates fit below the table
+func (m Model) diskPreviewHeight() int {
+	return max(m.height-20, 3)
+}
+
+// scrollDiskPreview moves the prune preview by delta candidates
+func (m *Model) scrollDiskPreview(delta int) {
+	last := max(len(m.diskUsage[m.diskCursor].Candidates)-m.diskPreviewHeight(), 0)
+	m.diskPreviewScroll = min(max(m.diskPreviewScroll+delta, 0), last)
+}
+
+// handleDiskUsageKey handles keys in the disk usage view
+func (m *Model) handleDiskUsageKey(msg tea.KeyMsg) tea.Cmd {
+	if m.confirmingDiskPrune {
+		switch msg.String() {
+		case "up", "k":
+			// Review every candidate before answering
+			m.scrollDiskPreview(-1)
+		case "down", "j":
+			m.scrollDiskPreview(1)
+		case "pgup":
+			m.scrollDiskPreview(-m.diskPreviewHeight())
+		case "pgdown":
+			m.scrollDiskPreview(m.diskPreviewHeight())
+		case "y", "Y":
+			m.confirmingDiskPrune = false
+			m.statusMsg = "Pruning..."
+			return m.pruneDisk(m.diskUsage[m.diskCursor].Category)
+		case "n", "N", "esc":
+			m.confirmingDiskPrune = false
+			m.statusMsg = "Prune cancelled"
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		// Ignore other keys during confirmation
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.currentView = viewList
+		m.statusMsg = ""
+	case "up", "k":
+		if m.diskCursor > 0 {
+			m.diskCursor--
+		}
+	case "down", "j":
+		if m.diskCursor < len(m.diskUsage)-1 {
+			m.diskCursor++
+		}
+	case "r", "f5":
+		m.diskLoading = true
+		return m.loadDiskUsage()
+	case "p", "enter":
+		// Prune the selected category - preview what goes and confirm
+		if m.diskCursor >= len(m.diskUsage) {
+			return nil
+		}
+		row := m.diskUsage[m.diskCursor]
+		if len(row.Candidates) == 0 {
+			m.statusMsg = "Nothing to prune: no " + row.Prune
+			return clearStatusAfterDelay(2 * time.Second)
+		}
+		m.confirmingDiskPrune = true
+		m.diskPreviewScroll = 0
+		m.statusMsg = fmt.Sprintf("⚠️  Prune %d %s (%s)? [y/n]",
+			len(row.Candidates), row.Prune, formatBytes(float64(row.candidatesSize()), false))
+	}
+	return nil
+}
+
+// statsSample is one ContainerStats reading, computed the way `docker stats` does
+type statsSample struct {
+	cpuPercent float64
+	memUsage   float64 // Bytes, excluding inactive page cache
+	memLimit   float64
+	memPercent float64
+	netRx      float64 // Bytes, summed over all interfaces
+	netTx      float64
+	blockRead  float64 // Bytes, summed over all devices
+	blockWrite float64
+	pids       uint64
+	time       time.Time
+}
+
+// statsSortKey selects the resource column the container list is sorted by
+type statsSortKey int
+
+const (
+	statsSortNone statsSortKey = iota
+	statsSortCPU
+	statsSortMem
+	statsSortNet
+	statsSortBlock
+)
+
+// String returns the column name shown in the status bar
+func (k statsSortKey) String() string {
+	switch k {
+	case statsSortCPU:
+		return "CPU %"
+	case statsSortMem:
+		return "memory"
+	case statsSortNet:
+		return "net I/O"
+	case statsSortBlock:
+		return "block I/O"
+	default:
+		return "default order"
+	}
+}
+
+// value returns the sample's value for the sort column (higher sorts first)
+func (k statsSortKey) value(s statsSample) float64 {
+	switch k {
+	case statsSortCPU:
+		return s.cpuPercent
+	case statsSortMem:
+		return s.memUsage
+	case statsSortNet:
+		return s.netRx + s.netTx
+	case statsSortBlock:
+		return s.blockRead + s.blockWrite
+	default:
+		return 0
+	}
+}
+
+// statsStream is an open ContainerStats stream for one container
+type statsStream struct {
+	cancel context.CancelFunc
+	detail bool // Feeds the stats view rather than the list columns
+}
+
+// statsMsg carries a new stats sample for a container
+type statsMsg struct {
+	id     string
+	stream *statsStream
+	sample statsSample
+}
+
+// statsEndedMsg signals that a container's stats stream has closed
+type statsEndedMsg struct {
+	id     string
+	stream *statsStream
+	err    error
+}
+
+// calculateStats converts a raw ContainerStats response into a sample,
+// using the same formulas as the docker CLI
+func calculateStats(v *container.StatsResponse) statsSample {
+	sample := statsSample{
+		pids: v.PidsStats.Current,
+		time: v.Read,
+	}
+
+	// CPU usage relative to the host, scaled by the number of CPUs
+	cpuDelta := float64(v.CPUStats.CPUUsage.TotalUsage) - float64(v.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(v.CPUStats.SystemUsage) - float64(v.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(v.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(v.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if systemDelta > 0 && cpuDelta > 0 {
+		sample.cpuPercent = cpuDelta / systemDelta * onlineCPUs * 100
+	}
+
+	// Memory usage excludes the inactive page cache (cgroup v1 and v2 names)
+	mem := v.MemoryStats
+	sample.memUsage = float64(mem.Usage)
+	if inactive, ok := mem.Stats["total_inactive_file"]; ok && inactive < mem.Usage {
+		sample.memUsage = float64(mem.Usage - inactive)
+	} else if inactive := mem.Stats["inactive_file"]; inactive < mem.Usage {
+		sample.memUsage = float64(mem.Usage - inactive)
+	}
+	sample.memLimit = float64(mem.Limit)
+	if mem.Limit != 0 {
+		sample.memPercent = sample.memUsage / sample.memLimit * 100
+	}
+
+	for _, network := range v.Networks {
+		sample.netRx += float64(network.RxBytes)
+		sample.netTx += float64(network.TxBytes)
+	}
+
+	for _, entry := range v.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			sample.blockRead += float64(entry.Value)
+		case "write":
+			sample.blockWrite += float64(entry.Value)
+		}
+	}
+	return sample
+}
+
+// formatBytes renders a byte count like the docker CLI: binary units
+// (MiB) for memory, decimal units (MB) for I/O
+func formatBytes(n float64, binary bool) string {
+	base, units, format := 1000.0, []string{"B", "kB", "MB", "GB", "TB"}, "%.3g%s"
+	if binary {
+		base, units, format = 1024.0, []string{"B", "KiB", "MiB", "GiB", "TiB"}, "%.4g%s"
+	}
+	i := 0
+	for n >= base && i < len(units)-1 {
+		n /= base
+		i++
+	}
+	return fmt.Sprintf(format, n, units[i])
+}
+
+// streamStats opens a streaming ContainerStats request and decodes samples
+// into ch until the stream ends or is cancelled. Everything, including a
+// failure to open the stream, is delivered through ch so its single reader
+// stays in step.
+func (m Model) streamStats(ctx context.Context, id string, stream *statsStream, ch chan<- tea.Msg) tea.Cmd {
+	cli := m.dockerClient
+	return func() tea.Msg {
+		go func() {
+			send := func(msg tea.Msg) bool {
+				select {
+				case ch <- msg:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
+
+			resp, err := cli.ContainerStats(ctx, id, true)
+			if err != nil {
+				send(statsEndedMsg{id: id, stream: stream, err: err})
+				return
+			}
+			defer resp.Body.Close()
+
+			decoder := json.NewDecoder(resp.Body)
+			for {
+				var v container.StatsResponse
+				if err := decoder.Decode(&v); err != nil {
+					if err == io.EOF || ctx.Err() != nil {
+						err = nil
+					}
+					send(statsEndedMsg{id: id, stream: stream, err: err})
+					return
+				}
+				if !send(statsMsg{id: id, stream: stream, sample: calculateStats(&v)}) {
+					return
+				}
+			}
+		}()
+		return nil
+	}
+}
+
+// waitForStats returns a command that blocks until the next stats message arrives
+func waitForStats(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// syncStatsStreams opens stats streams for shown running containers and
+// closes the ones no longer needed
+func (m *Model) syncStatsStreams() tea.Cmd {
+	wanted := make(map[string]bool)
+	if m.showStats {
+		for _, c := range m.containers {
+			if c.State == "running" {
+				wanted[c.ID] = true
+			}
+		}
+	}
+
+	for id, stream := range m.statsStreams {
+		if !wanted[id] {
+			stream.cancel()
+			delete(m.statsStreams, id)
+			delete(m.stats, id)
+		}
+	}
+
+	var cmds []tea.Cmd
+	for id := range wanted {
+		if m.statsStreams[id] != nil {
+			continue
+		}
+		if m.statsStreams == nil {
+			m.statsStreams = make(map[string]*statsStream)
+		}
+		ctx, cancel := context.WithCancel(m.ctx)
+		stream := &statsStream{cancel: cancel}
+		m.statsStreams[id] = stream
+		cmds = append(cmds, m.streamStats(ctx, id, stream, m.statsCh))
+	}
+	return tea.Batch(cmds...)
+}
+
+// toggleStats shows or hides the resource columns, starting or stopping
+// the stats streams to match
+func (m *Model) toggleStats() tea.Cmd {
+	m.showStats = !m.showStats
+	if !m.showStats {
+		m.statsSort = statsSortNone
+		m.filterContainers()
+		return m.syncStatsStreams()
+	}
+
+	// All streams share one channel with a single reader
+	first := m.statsCh == nil
+	if first {
+		m.statsCh = make(chan tea.Msg)
+	}
+	if m.stats == nil {
+		m.stats = make(map[string]statsSample)
+	}
+	cmd := m.syncStatsStreams()
+	if first {
+		return tea.Batch(cmd, waitForStats(m.statsCh))
+	}
+	return cmd
+}
+
+// statsHistoryLen is how many samples the stats view keeps, about three
+// minutes at the daemon's rate of one sample per second
+const statsHistoryLen = 180
+
+// openStatsView starts a dedicated stats stream for the container under the cursor
+func (m *Model) openStatsView() tea.Cmd {
+	if len(m.containers) == 0 {
+		m.statusMsg = "Error: no container selected"
+		return nil
+	}
+	c := m.containers[m.cursor]
+
+	m.closeStatsView()
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.statsViewStream = &statsStream{cancel: cancel, detail: true}
+	m.statsViewCh = make(chan tea.Msg)
+	m.statsViewID = c.ID
+	m.statsViewName = c.Name
+	m.statsHistory = nil
+	m.statsViewEnded = false
+	m.currentView = viewStats
+	m.statusMsg = ""
+	return tea.Batch(
+		m.streamStats(ctx, c.ID, m.statsViewStream, m.statsViewCh),
+		waitForStats(m.statsViewCh),
+	)
+}
+
+// closeStatsView cancels the stats view's stream
+func (m *Model) closeStatsView() {
+	if m.statsViewStream != nil {
+		m.statsViewStream.cancel()
+		m.statsViewStream = nil
+	}
+	m.statsViewCh = nil
+}
+
+// statsRates converts a cumulative counter into per-second rates between
+// consecutive samples
+func statsRates(history []statsSample, counter func(statsSample) float64) []float64 {
+	var rates []float64
+	for i := 1; i < len(history); i++ {
+		rate := 0.0
+		if elapsed := history[i].time.Sub(history[i-1].time).Seconds(); elapsed > 0 {
+			// Counters reset when the container restarts
+			rate = max(0, (counter(history[i])-counter(history[i-1]))/elapsed)
+		}
+		rates = append(rates, rate)
+	}
+	return rates
+}
+
+// sparklineLevels are the bar heights used by sparkline, lowest first
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the newest width values as bars scaled to the largest
+// shown value, right-aligned so the graph scrolls from the right
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if peak > 0 {
+			level = int(v / peak * float64(len(sparklineLevels)-1))
+		}
+		b.WriteRune(sparklineLevels[level])
+	}
+	return b.String()
+}
+
+// maxLogLines bounds the logs view's memory; older lines are discarded
+const maxLogLines = 5000
+
+// logBatchSize caps how many lines are applied per update so a noisy
+// container can't starve the UI
+const logBatchSize = 500
+
+// logLine is a single line of container output
+type logLine struct {
+	text   string
+	stderr bool      // Line was written to stderr
+	source int       // Index into the logs view's sources
+	time   time.Time // Daemon timestamp (zero unless timestamps are enabled)
+}
+
+// logSource is one container feeding the logs view
+type logSource struct {
+	id     string
+	name   string
+	tty    bool // Output is a raw TTY stream rather than multiplexed
+	hidden bool // Toggled off in the merged view
+}
+
+// logSourceColors are cycled through for per-container prefixes in the merged view
+var logSourceColors = []lipgloss.Color{
+	lipgloss.Color("#00D9FF"),
+	lipgloss.Color("#FFD700"),
+	lipgloss.Color("#00FF87"),
+	lipgloss.Color("#FF87FF"),
+	lipgloss.Color("#FF875F"),
+	lipgloss.Color("#87AFFF"),
+}
+
+// logStreamFilter selects which output streams the logs view shows
+type logStreamFilter int
+
+const (
+	logStreamsBoth logStreamFilter = iota
+	logStreamsStdout
+	logStreamsStderr
+)
+
+// String returns the filter's display name
+func (f logStreamFilter) String() string {
+	switch f {
+	case logStreamsStdout:
+		return "stdout"
+	case logStreamsStderr:
+		return "stderr"
+	default:
+		return "both"
+	}
+}
+
+// shows reports whether a line passes the filter
+func (f logStreamFilter) shows(line logLine) bool {
+	switch f {
+	case logStreamsStdout:
+		return !line.stderr
+	case logStreamsStderr:
+		return line.stderr
+	default:
+		return true
+	}
+}
+
+// logBuffer is a fixed-capacity ring buffer of log lines. Once full, each
+// new line overwrites the oldest, so following a noisy container for hours
+// uses constant memory.
+type logBuffer struct {
+	lines []logLine
+	start int // Index of the oldest line
+	count int // Number of lines held
+	total int // Number of lines ever appended
+}
+
+// newLogBuffer creates an empty buffer holding at most capacity lines
+func newLogBuffer(capacity int) *logBuffer {
+	return &logBuffer{lines: make([]logLine, capacity)}
+}
+
+// Append adds lines, evicting the oldest when the buffer is full. Lines
+// carrying a timestamp are slotted in by time, so output from several
+// containers interleaves correctly even when it arrives out of order. It
+// returns the absolute position each line went in at, in order, so anchors
+// into the buffer can be moved past lines inserted above them.
+func (b *logBuffer) Append(lines ...logLine) []int {
+	positions := make([]int, 0, len(lines))
+	for _, line := range lines {
+		b.total++
+		if b.count < len(b.lines) {
+			b.count++
+		} else {
+			b.start = (b.start + 1) % len(b.lines)
+		}
+
+		// Shift newer timestamped lines up to make room
+		i := b.count - 1
+		for ; i > 0 && !line.time.IsZero(); i-- {
+			prev := b.at(i - 1)
+			if prev.time.IsZero() || !prev.time.After(line.time) {
+				break
+			}
+			*b.at(i) = *prev
+		}
+		*b.at(i) = line
+		positions = append(positions, b.Dropped()+i)
+	}
+	return positions
+}
+
+// at returns a pointer to the i-th held line
+func (b *logBuffer) at(i int) *logLine {
+	return &b.lines[(b.start+i)%len(b.lines)]
+}
+
+// Len returns the number of lines held
+func (b *logBuffer) Len() int {
+	return b.count
+}
+
+// Line returns the i-th held line, where 0 is the oldest
+func (b *logBuffer) Line(i int) logLine {
+	return *b.at(i)
+}
+
+// Dropped returns how many lines have been evicted
+func (b *logBuffer) Dropped() int {
+	return b.total - b.count
+}
+
+// logStream pumps lines from one or more container log readers into a channel
+type logStream struct {
+	lines chan logLine
+	err   error // Set before lines is closed
+}
+
+// logLineWriter splits written bytes into lines tagged with their stream
+// and source, parsing the daemon's timestamp prefix when present
+type logLineWriter struct {
+	ctx        context.Context
+	lines      chan<- logLine
+	stderr     bool
+	source     int
+	timestamps bool
+	partial    []byte // Incomplete last line
+}
+
+// Write emits every complete line in p, blocking until each is consumed
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			return len(p), nil
+		}
+		if err := w.emit(w.partial[:idx]); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[idx+1:]
+	}
+}
+
+// Flush emits any trailing text that wasn't newline-terminated
+func (w *logLineWriter) Flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+// emit sends one line, giving up if the stream is cancelled
+func (w *logLineWriter) emit(text []byte) error {
+	line := logLine{text: strings.TrimRight(string(text), "\r"), stderr: w.stderr, source: w.source}
+	if w.timestamps {
+		if stamp, _, ok := strings.Cut(line.text, " "); ok {
+			line.time, _ = time.Parse(time.RFC3339Nano, stamp)
+		}
+	}
+	select {
+	case w.lines <- line:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// pumpLogs copies one container's log stream into lines until it ends.
+// Non-TTY containers multiplex stdout and stderr behind 8-byte frame
+// headers, which are split apart with stdcopy; TTY containers produce a
+// single raw stream.
+func pumpLogs(ctx context.Context, r io.ReadCloser, tty bool, source int, timestamps bool, lines chan<- logLine) error {
+	defer r.Close()
+	stdout := &logLineWriter{ctx: ctx, lines: lines, source: source, timestamps: timestamps}
+	stderr := &logLineWriter{ctx: ctx, lines: lines, source: source, timestamps: timestamps, stderr: true}
+
+	var err error
+	if tty {
+		_, err = io.Copy(stdout, r)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, r)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	stdout.Flush()
+	stderr.Flush()
+	return err
+}
+
+// newLogStream starts reading every reader in the background, one per
+// source in order. The channel is closed once all readers are exhausted
+// or ctx is cancelled.
+func newLogStream(ctx context.Context, readers []io.ReadCloser, sources []logSource, timestamps bool) *logStream {
+	s := &logStream{lines: make(chan logLine, logBatchSize)}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, r := range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pumpLogs(ctx, r, sources[i].tty, i, timestamps, s.lines); err != nil {
+				mu.Lock()
+				s.err = fmt.Errorf("%s: %w", sources[i].name, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(s.lines)
+	}()
+	return s
+}
+
+// waitForLogLines blocks until at least one line is available, then drains
+// whatever else is already buffered (up to logBatchSize) into one message
+func waitForLogLines(streamID int, s *logStream) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-s.lines
+		if !ok {
+			return logsStreamEndedMsg{streamID: streamID, err: s.err}
+		}
+
+		batch := []logLine{line}
+		for len(batch) < logBatchSize {
+			select {
+			case line, ok := <-s.lines:
+				if !ok {
+					return logLinesMsg{streamID: streamID, lines: batch}
+				}
+				batch = append(batch, line)
+			default:
+				return logLinesMsg{streamID: streamID, lines: batch}
+			}
+		}
+		return logLinesMsg{streamID: streamID, lines: batch}
+	}
+}
+
+// defaultLogsTail is how many existing lines are fetched before following
+const defaultLogsTail = "100"
+
+// viewContainerLogs opens a follow-mode log stream for the selected
+// containers, merged by time, or for the container under the cursor when
+// nothing is selected
+func (m *Model) viewContainerLogs() tea.Cmd {
+	targets := m.selectedContainers()
+	if len(targets) == 0 {
+		targets = m.actionTargets()
+	}
+	if len(targets) == 0 {
+		m.statusMsg = "Error: no container selected"
+		return nil
+	}
+	var sources []logSource
+	for _, c := range targets {
+		sources = append(sources, logSource{id: c.ID, name: c.Name})
+	}
+
+	m.logsWrap = false
+	m.logsSearch = nil
+	m.logsStreams = logStreamsBoth
+	return m.openLogs(sources)
+}
+
+// openLogs (re)starts the log streams for the given sources using the
+// current tail and since settings. Merged views always request timestamps
+// so lines can be interleaved by time.
+func (m *Model) openLogs(sources []logSource) tea.Cmd {
+	m.stopLogs()
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.logsCancel = cancel
+	m.logsStreamID++
+	m.logsSources = sources
+	m.logsFollow = true
+
+	tail := m.logsTail
+	if tail == "" {
+		tail = defaultLogsTail
+	}
+	timestamps := m.logsTimestamps || len(sources) > 1
+	streamID := m.logsStreamID
+	cli := m.dockerClient
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Tail:       tail,
+		Since:      m.logsSince,
+		Timestamps: timestamps,
+	}
+	// Copy so the command doesn't race with toggles on the model's slice
+	sources = append([]logSource(nil), sources...)
+	return func() tea.Msg {
+		var readers []io.ReadCloser
+		fail := func(name string, err error) tea.Msg {
+			for _, r := range readers {
+				r.Close()
+			}
+			return logsStreamMsg{streamID: streamID, err: fmt.Errorf("%s: %w", name, err)}
+		}
+
+		for i, source := range sources {
+			// TTY containers don't multiplex their output
+			inspect, err := cli.ContainerInspect(ctx, source.id)
+			if err != nil {
+				return fail(source.name, err)
+			}
+			sources[i].tty = inspect.Config != nil && inspect.Config.Tty
+
+			logs, err := cli.ContainerLogs(ctx, source.id, options)
+			if err != nil {
+				return fail(source.name, err)
+			}
+			readers = append(readers, logs)
+		}
+		return logsStreamMsg{streamID: streamID, stream: newLogStream(ctx, readers, sources, timestamps)}
+	}
+}
+
+// validateLogsTail checks a tail value is "all" or a non-negative line count
+func validateLogsTail(value string) error {
+	if value == "all" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < 0 {
+		return fmt.Errorf("tail must be a line count or \"all\"")
+	}
+	return nil
+}
+
+// validateLogsSince checks a since value is empty, a duration ("10m"), an
+// RFC3339 timestamp or a Unix timestamp, the forms the daemon accepts
+func validateLogsSince(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(value); err == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return nil
+	}
+	return fmt.Errorf("since must be a duration (10m), RFC3339 time or Unix timestamp")
+}
+
+// stopLogs cancels any active log stream and clears the logs view state
+func (m *Model) stopLogs() {
+	if m.logsCancel != nil {
+		m.logsCancel()
+	}
+	m.logsCancel = nil
+	m.logsStream = nil
+	m.logsBuffer = nil
+	m.logsPaused = false
+	m.logsWaiting = false
+	m.logsEnded = false
+	m.logsPrompt = ""
+	m.logsPromptInput = ""
+}
+
+// logsPageHeight returns how many screen rows the logs pager can use
+func (m Model) logsPageHeight() int {
+	// Title, divider, blank, position line, status and help box take 10 rows
+	return max(m.height-10, 5)
+}
+
+// logsMerged reports whether the logs view shows more than one container
+func (m Model) logsMerged() bool {
+	return len(m.logsSources) > 1
+}
+
+// logsPrefixWidth returns the width of the "name | " prefix used in the merged view
+func (m Model) logsPrefixWidth() int {
+	if !m.logsMerged() {
+		return 0
+	}
+	width := 0
+	for _, source := range m.logsSources {
+		width = max(width, len(source.name))
+	}
+	return min(width, 20) + 3
+}
+
+// logsLineWidth returns the width available for a log line's text
+func (m Model) logsLineWidth() int {
+	return max(m.width, 40) - m.logsPrefixWidth()
+}
+
+// logSegments splits a log line into screen rows: wrapped into chunks
+// of width when wrapping, otherwise a single truncated row
+func logSegments(text string, width int, wrap bool) []string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return []string{text}
+	}
+	if !wrap {
+		return []string{string(runes[:width-3]) + "..."}
+	}
+	var segments []string
+	for len(runes) > width {
+		segments = append(segments, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(segments, string(runes))
+}
+
+// logsVisibleLines returns the buffer indices of the lines that pass the
+// stream filter, oldest first. Pager positions index into this slice.
+func (m Model) logsVisibleLines() []int {
+	if m.logsBuffer == nil {
+		return nil
+	}
+	visible := make([]int, 0, m.logsBuffer.Len())
+	for i := 0; i < m.logsBuffer.Len(); i++ {
+		line := m.logsBuffer.Line(i)
+		if !m.logsStreams.shows(line) {
+			continue
+		}
+		if line.source < len(m.logsSources) && m.logsSources[line.source].hidden {
+			continue
+		}
+		visible = append(visible, i)
+	}
+	return visible
+}
+
+// logsBottomStart returns the position of the first line shown when the
+// pager is scrolled to the end, accounting for wrapped lines
+func (m Model) logsBottomStart(visible []int) int {
+	width, page := m.logsLineWidth(), m.logsPageHeight()
+	rows := 0
+	start := len(visible)
+	for start > 0 {
+		n := len(logSegments(m.logsBuffer.Line(visible[start-1]).text, width, m.logsWrap))
+		if rows+n > page {
+			break
+		}
+		rows += n
+		start--
+	}
+	// A single line taller than the page still gets shown
+	if start == len(visible) && start > 0 {
+		start--
+	}
+	return start
+}
+
+// logsStart returns the position of the first line currently shown
+func (m Model) logsStart(visible []int) int {
+	bottom := m.logsBottomStart(visible)
+	if m.logsFollow {
+		return bottom
+	}
+	// First visible line at or after the remembered top line
+	top := m.logsTop - m.logsBuffer.Dropped()
+	pos := sort.SearchInts(visible, top)
+	return min(pos, bottom)
+}
+
+// scrollLogs moves the pager by delta lines, re-enabling follow mode
+// when the end is reached
+func (m *Model) scrollLogs(delta int) {
+	visible := m.logsVisibleLines()
+	if len(visible) == 0 {
+		return
+	}
+	pos := m.logsStart(visible) + delta
+	if pos >= m.logsBottomStart(visible) {
+		m.logsFollow = true
+		return
+	}
+	m.logsFollow = false
+	m.logsTop = m.logsBuffer.Dropped() + visible[max(pos, 0)]
+}
+
+// findLogMatch returns the position of the next line (searching forward or
+// backward from the current position) matching the search pattern, or -1
+func (m Model) findLogMatch(visible []int, forward bool) int {
+	if m.logsSearch == nil {
+		return -1
+	}
+	total := len(visible)
+	start := m.logsStart(visible)
+	for step := 1; step <= total; step++ {
+		pos := start - step
+		if forward {
+			pos = start + step
+		}
+		pos = ((pos % total) + total) % total // Wrap around
+		if m.logsSearch.MatchString(m.logsBuffer.Line(visible[pos]).text) {
+			return pos
+		}
+	}
+	return -1
+}
+
+// jumpToLogMatch scrolls the pager so the next/previous match is at the top
+func (m *Model) jumpToLogMatch(forward bool) {
+	visible := m.logsVisibleLines()
+	pos := m.findLogMatch(visible, forward)
+	if pos < 0 {
+		m.statusMsg = "Pattern not found"
+		return
+	}
+	m.statusMsg = ""
+	m.logsFollow = false
+	m.logsTop = m.logsBuffer.Dropped() + visible[pos]
+}
+
+// handleLogsPromptKey handles typing into the search/tail/since prompt
+func (m *Model) handleLogsPromptKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.logsPrompt = ""
+		m.logsPromptInput = ""
+	case "enter":
+		prompt, value := m.logsPrompt, strings.TrimSpace(m.logsPromptInput)
+		m.logsPrompt = ""
+		m.logsPromptInput = ""
+		switch prompt {
+		case "search":
+			if value == "" {
+				m.logsSearch = nil
+				return nil
+			}
+			re, err := regexp.Compile(value)
+			if err != nil {
+				m.statusMsg = fmt.Sprintf("Invalid pattern: %v", err)
+				return nil
+			}
+			m.logsSearch = re
+			m.jumpToLogMatch(true)
+		case "tail":
+			if value == "" {
+				value = defaultLogsTail
+			}
+			if err := validateLogsTail(value); err != nil {
+				m.statusMsg = err.Error()
+				return nil
+			}
+			m.logsTail = value
+			return m.openLogs(m.logsSources)
+		case "since":
+			if err := validateLogsSince(value); err != nil {
+				m.statusMsg = err.Error()
+				return nil
+			}
+			m.logsSince = value
+			return m.openLogs(m.logsSources)
+		}
+	case "backspace":
+		if len(m.logsPromptInput) > 0 {
+			runes := []rune(m.logsPromptInput)
+			m.logsPromptInput = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.logsPromptInput += msg.String()
+		}
+	}
+	return nil
+}
+
+// waitForLogs schedules the next read from the active log stream unless
+// one is already in flight or the view is paused
+func (m *Model) waitForLogs() tea.Cmd {
+	if m.logsStream == nil || m.logsPaused || m.logsWaiting {
+		return nil
+	}
+	m.logsWaiting = true
+	return waitForLogLines(m.logsStreamID, m.logsStream)
+}
+
+// shellIntoContainer opens an interactive shell popup for the selected container
+func (m *Model) shellIntoContainer() tea.Cmd {
+	if len(m.containers) == 0 {
+		return nil
+	}
+
+	containerID := m.containers[m.cursor].ID
+	containerName := m.containers[m.cursor].Name
+
+	// Initialize shell state
+	cols, rows := shellTermSize(m.width, m.height)
+	m.shellContainerID = containerID
+	m.shellContainerName = containerName
+	m.shellScreen = newTerminalScreen(cols, rows)
+	m.shellConn = nil
+	m.shellExecID = ""
+	m.shellInput = ""
+	m.shellSessionID++
+
+	// Switch to shell view
+	m.currentView = viewShell
+
+	return m.startShellSession(containerID, cols, rows)
+}
+
+// startShellSession creates a long-lived TTY exec in the container and attaches to it
+func (m Model) startShellSession(containerID string, cols, rows int) tea.Cmd {
+	sessionID := m.shellSessionID
+	return func() tea.Msg {
+		consoleSize := [2]uint{uint(rows), uint(cols)}
+
+		// Prefer bash when the image has it, otherwise fall back to sh
+		execConfig := container.ExecOptions{
+			Tty:          true,
+			AttachStdin:  true,
+			AttachStdout: true,
+			AttachStderr: true,
+			ConsoleSize:  &consoleSize,
+			Env:          []string{"TERM=xterm"},
+			Cmd:          []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash; exec sh"},
+		}
+
+		execResp, err := m.dockerClient.ContainerExecCreate(m.ctx, containerID, execConfig)
+		if err != nil {
+			return shellReadyMsg{sessionID: sessionID, err: fmt.Errorf("failed to create exec: %w", err)}
+		}
+
+		attachResp, err := m.dockerClient.ContainerExecAttach(m.ctx, execResp.ID, container.ExecAttachOptions{
+			Tty:         true,
+			ConsoleSize: &consoleSize,
+		})
+		if err != nil {
+			return shellReadyMsg{sessionID: sessionID, err: fmt.Errorf("failed to attach: %w", err)}
+		}
+
+		return shellReadyMsg{sessionID: sessionID, containerID: containerID, execID: execResp.ID, conn: &attachResp}
+	}
+}
+
+// readShellOutput returns a command that reads the next chunk of raw TTY output
+func readShellOutput(execID string, conn *types.HijackedResponse) tea.Cmd {
+	return func() tea.Msg {
+		buf := make([]byte, 32*1024)
+		n, err := conn.Reader.Read(buf)
+		if n > 0 {
+			return shellOutputMsg{execID: execID, data: buf[:n]}
+		}
+		return shellExitedMsg{execID: execID, err: err}
+	}
+}
+
+// resizeShell tells the daemon the TTY size changed
+func (m Model) resizeShell(cols, rows int) tea.Cmd {
+	execID := m.shellExecID
+	return func() tea.Msg {
+		// A failed resize only affects layout, so it is not reported
+		_ = m.dockerClient.ContainerExecResize(m.ctx, execID, container.ResizeOptions{
+			Height: uint(rows),
+			Width:  uint(cols),
+		})
+		return nil
+	}
+}
+
+// sendShellInput forwards raw bytes to the shell's TTY. Input typed before
+// the exec session is attached is buffered in shellInput and flushed once
+// the session is ready.
+func (m *Model) sendShellInput(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	if m.shellConn == nil {
+		m.shellInput += string(data)
+		return
+	}
+	if _, err := m.shellConn.Conn.Write(data); err != nil {
+		m.statusMsg = fmt.Sprintf("Shell write failed: %v", err)
+	}
+}
+
+// closeShell detaches from the shell session and returns to the list view.
+// The shell itself is stopped separately by hangUpShell.
+func (m *Model) closeShell() {
+	if m.shellConn != nil {
+		m.shellConn.Close()
+	}
+	m.currentView = viewList
+	m.shellConn = nil
+	m.shellScreen = nil
+	m.shellInput = ""
+	m.shellExecID = ""
+}
+
+// hangUpShell sends SIGHUP to a detached shell. Closing the attach stream
+// leaves the exec'd shell and its children running in the container, so the
+// shell's host PID from ContainerExecInspect is looked up inside the
+// container and signalled there, which also hangs up its jobs.
+func (m Model) hangUpShell(containerID, execID string) tea.Cmd {
+	cli, ctx := m.dockerClient, m.ctx
+	return func() tea.Msg {
+		inspect, err := cli.ContainerExecInspect(ctx, execID)
+		if err != nil {
+			return shellHungUpMsg{err}
+		}
+		if !inspect.Running {
+			return shellHungUpMsg{}
+		}
+		pid, err := resolveContainerPID(ctx, cli, containerID, strconv.Itoa(inspect.Pid))
+		if err != nil {
+			return shellHungUpMsg{err}
+		}
+		_, stderr, code, err := runExec(ctx, cli, containerID, []string{"kill", "-HUP", pid})
+		if err == nil && code != 0 {
+			err = errors.New(strings.TrimSpace(stderr))
+		}
+		return shellHungUpMsg{err}
+	}
+}
+
+// shellKeyBytes translates a key press into the byte sequence a terminal would send
+func shellKeyBytes(msg tea.KeyMsg, appCursorKeys bool) []byte {
+	var b []byte
+
+	switch msg.Type {
+	case tea.KeyRunes:
+		b = []byte(string(msg.Runes))
+	case tea.KeySpace:
+		b = []byte(" ")
+	case tea.KeyUp, tea.KeyDown, tea.KeyRight, tea.KeyLeft:
+		dir := map[tea.KeyType]byte{tea.KeyUp: 'A', tea.KeyDown: 'B', tea.KeyRight: 'C', tea.KeyLeft: 'D'}[msg.Type]
+		if appCursorKeys {
+			b = []byte{0x1b, 'O', dir}
+		} else {
+			b = []byte{0x1b, '[', dir}
+		}
+	case tea.KeyHome:
+		b = []byte("\x1b[H")
+	case tea.KeyEnd:
+		b = []byte("\x1b[F")
+	case tea.KeyPgUp:
+		b = []byte("\x1b[5~")
+	case tea.KeyPgDown:
+		b = []byte("\x1b[6~")
+	case tea.KeyDelete:
+		b = []byte("\x1b[3~")
+	case tea.KeyInsert:
+		b = []byte("\x1b[2~")
+	case tea.KeyShiftTab:
+		b = []byte("\x1b[Z")
+	case tea.KeyF1, tea.KeyF2, tea.KeyF3, tea.KeyF4:
+		b = []byte{0x1b, 'O', byte('P' + (msg.Type - tea.KeyF1))}
+	case tea.KeyF5, tea.KeyF6, tea.KeyF7, tea.KeyF8, tea.KeyF9, tea.KeyF10, tea.KeyF11, tea.KeyF12:
+		codes := []int{15, 17, 18, 19, 20, 21, 23, 24}
+		b = []byte(fmt.Sprintf("\x1b[%d~", codes[msg.Type-tea.KeyF5]))
+	default:
+		// Control keys (ctrl+a..ctrl+_, enter, tab, esc, backspace) map
+		// directly to their ASCII control codes
+		if msg.Type >= 0 && msg.Type <= 127 {
+			b = []byte{byte(msg.Type)}
+		}
+	}
+
+	if msg.Alt && len(b) > 0 {
+		b = append([]byte{0x1b}, b...)
+	}
+	return b
+}
+
+// Parser states for terminalScreen
+const (
+	termGround = iota
+	termEscape
+	termCSI
+	termOSC
+	termOSCEscape
+	termCharset
+)
+
+// terminalScreen is a minimal VT100/xterm emulator used to render the
+// interactive shell. It keeps a fixed grid of cells plus the cursor and
+// understands the control sequences that shells, top and vim rely on.
+// Colours and text attributes are parsed but not rendered.
+type terminalScreen struct {
+	cols, rows       int
+	cells            [][]rune
+	cursorX, cursorY int
+	savedX, savedY   int
+	scrollTop        int
+	scrollBottom     int
+	wrapNext         bool     // Cursor is past the last column; next rune wraps
+	cursorHidden     bool     // DECTCEM
+	appCursorKeys    bool     // DECCKM - arrow keys send ESC O x
+	primaryCells     [][]rune // Main screen saved while the alternate screen is active
+	state            int      // Parser state
+	params           []byte   // Collected CSI parameter bytes
+	pending          []byte   // Incomplete UTF-8 sequence carried between writes
+}
+
+// newTerminalScreen creates a blank screen of the given size
+func newTerminalScreen(cols, rows int) *terminalScreen {
+	t := &terminalScreen{}
+	t.Resize(cols, rows)
+	return t
+}
+
+// blankGrid returns a grid of spaces
+func blankGrid(cols, rows int) [][]rune {
+	grid := make([][]rune, rows)
+	for y := range grid {
+		grid[y] = blankLine(cols)
+	}
+	return grid
+}
+
+// blankLine returns a row of spaces
+func blankLine(cols int) []rune {
+	line := make([]rune, cols)
+	for x := range line {
+		line[x] = ' '
+	}
+	return line
+}
+
+// Resize changes the screen size, keeping as much of the top-left content as fits
+func (t *terminalScreen) Resize(cols, rows int) {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+
+	t.cols, t.rows = cols, rows
+	t.cells = resizeGrid(t.cells, cols, rows)
+	if t.primaryCells != nil {
+		t.primaryCells = resizeGrid(t.primaryCells, cols, rows)
+	}
+	t.scrollTop, t.scrollBottom = 0, rows-1
+	t.cursorX = min(t.cursorX, cols-1)
+	t.cursorY = min(t.cursorY, rows-1)
+	t.savedX = min(t.savedX, cols-1)
+	t.savedY = min(t.savedY, rows-1)
+	t.wrapNext = false
+}
+
+// restoreCursor moves the cursor back to the saved position, kept inside
+// the grid in case it was saved before a resize
+func (t *terminalScreen) restoreCursor() {
+	t.cursorX = max(min(t.savedX, t.cols-1), 0)
+	t.cursorY = max(min(t.savedY, t.rows-1), 0)
+	t.wrapNext = false
+}
+
+// resizeGrid returns a copy of grid with the given size, clipping or padding with spaces
+func resizeGrid(grid [][]rune, cols, rows int) [][]rune {
+	resized := blankGrid(cols, rows)
+	for y := 0; y < rows && y < len(grid); y++ {
+		copy(resized[y], grid[y])
+	}
+	return resized
+}
+
+// Lines returns the screen contents, one string per row
+func (t *terminalScreen) Lines() []string {
+	lines := make([]string, t.rows)
+	for y, row := range t.cells {
+		lines[y] = string(row)
+	}
+	return lines
+}
+
+// Write feeds raw TTY output into the emulator
+func (t *terminalScreen) Write(p []byte) (int, error) {
+	data := p
+	if len(t.pending) > 0 {
+		data = append(t.pending, p...)
+		t.pending = nil
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+
+		// Multi-byte UTF-8 runes are only meaningful as printable text
+		if c >= 0x80 && t.state == termGround {
+			if !utf8.FullRune(data[i:]) {
+				t.pending = append([]byte(nil), data[i:]...)
+				break
+			}
+			r, size := utf8.DecodeRune(data[i:])
+			t.put(r)
+			i += size
+			continue
+		}
+
+		t.step(c)
+		i++
+	}
+	return len(p), nil
+}
+
+// step advances the parser by one byte
+func (t *terminalScreen) step(c byte) {
+	switch t.state {
+	case termGround:
+		t.control(c)
+	case termEscape:
+		t.escape(c)
+	case termCSI:
+		switch {
+		case c >= 0x30 && c <= 0x3f:
+			t.params = append(t.params, c)
+		case c >= 0x20 && c <= 0x2f:
+			// Intermediate bytes are not used by any sequence we handle
+		case c >= 0x40 && c <= 0x7e:
+			t.csi(c)
+			t.state = termGround
+		case c == 0x1b:
+			t.state = termEscape
+		default:
+			t.control(c)
+		}
+	case termOSC:
+		switch c {
+		case 0x07:
+			t.state = termGround
+		case 0x1b:
+			t.state = termOSCEscape
+		}
+	case termOSCEscape:
+		// ESC \ (string terminator) ends the OSC; anything else is dropped too
+		t.state = termGround
+	case termCharset:
+		// Character set designation is ignored
+		t.state = termGround
+	}
+}
+
+// control handles a byte in the ground state
+func (t *terminalScreen) control(c byte) {
+	switch c {
+	case 0x1b:
+		t.state = termEscape
+	case '\r':
+		t.cursorX = 0
+		t.wrapNext = false
+	case '\n', '\v', '\f':
+		t.lineFeed()
+	case '\b':
+		if t.cursorX > 0 {
+			t.cursorX--
+		}
+		t.wrapNext = false
+	case '\t':
+		t.cursorX = min((t.cursorX/8+1)*8, t.cols-1)
+	default:
+		if c >= 0x20 && c < 0x7f {
+			t.put(rune(c))
+		}
+	}
+}
+
+// escape handles the byte following ESC
+func (t *terminalScreen) escape(c byte) {
+	t.state = termGround
+	switch c {
+	case '[':
+		t.state = termCSI
+		t.params = t.params[:0]
+	case ']':
+		t.state = termOSC
+	case '(', ')', '*', '+':
+		t.state = termCharset
+	case '7':
+		t.savedX, t.savedY = t.cursorX, t.cursorY
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.cursorX = 0
+		t.lineFeed()
+	case 'M':
+		t.reverseIndex()
+	case 'c':
+		cols, rows := t.cols, t.rows
+		*t = terminalScreen{}
+		t.Resize(cols, rows)
+	}
+}
+
+// csiParams parses the collected CSI parameters, reporting a private-mode prefix
+func (t *terminalScreen) csiParams() (params []int, private byte) {
+	raw := string(t.params)
+	if raw != "" && (raw[0] == '?' || raw[0] == '>' || raw[0] == '=') {
+		private = raw[0]
+		raw = raw[1:]
+	}
+	if raw == "" {
+		return nil, private
+	}
+	for _, field := range strings.Split(raw, ";") {
+		// Sub-parameters (e.g. 38:2:r:g:b) only appear in SGR, which is ignored
+		if idx := strings.IndexByte(field, ':'); idx >= 0 {
+			field = field[:idx]
+		}
+		n, _ := strconv.Atoi(field)
+		params = append(params, n)
+	}
+	return params, private
+}
+
+// csi executes a complete control sequence
+func (t *terminalScreen) csi(final byte) {
+	params, private := t.csiParams()
+	arg := func(i, def int) int {
+		if i < len(params) && params[i] > 0 {
+			return params[i]
+		}
+		return def
+	}
+
+	if private == '>' || private == '=' {
+		return
+	}
+	if private == '?' {
+		if final == 'h' || final == 'l' {
+			t.setPrivateModes(params, final == 'h')
+		}
+		return
+	}
+
+	t.wrapNext = false
+	switch final {
+	case 'A':
+		t.cursorY = max(t.cursorY-arg(0, 1), 0)
+	case 'B', 'e':
+		t.cursorY = min(t.cursorY+arg(0, 1), t.rows-1)
+	case 'C', 'a':
+		t.cursorX = min(t.cursorX+arg(0, 1), t.cols-1)
+	case 'D':
+		t.cursorX = max(t.cursorX-arg(0, 1), 0)
+	case 'E':
+		t.cursorY = min(t.cursorY+arg(0, 1), t.rows-1)
+		t.cursorX = 0
+	case 'F':
+		t.cursorY = max(t.cursorY-arg(0, 1), 0)
+		t.cursorX = 0
+	case 'G', '`':
+		t.cursorX = min(arg(0, 1)-1, t.cols-1)
+	case 'd':
+		t.cursorY = min(arg(0, 1)-1, t.rows-1)
+	case 'H', 'f':
+		t.cursorY = min(arg(0, 1)-1, t.rows-1)
+		t.cursorX = min(arg(1, 1)-1, t.cols-1)
+	case 'J':
+		t.eraseDisplay(arg(0, 0))
+	case 'K':
+		t.eraseLine(arg(0, 0))
+	case 'L':
+		if t.cursorY >= t.scrollTop && t.cursorY <= t.scrollBottom {
+			t.scrollDownRegion(t.cursorY, t.scrollBottom, arg(0, 1))
+		}
+	case 'M':
+		if t.cursorY >= t.scrollTop && t.cursorY <= t.scrollBottom {
+			t.scrollUpRegion(t.cursorY, t.scrollBottom, arg(0, 1))
+		}
+	case 'S':
+		t.scrollUpRegion(t.scrollTop, t.scrollBottom, arg(0, 1))
+	case 'T':
+		t.scrollDownRegion(t.scrollTop, t.scrollBottom, arg(0, 1))
+	case 'P':
+		row := t.cells[t.cursorY]
+		n := min(arg(0, 1), t.cols-t.cursorX)
+		copy(row[t.cursorX:], row[t.cursorX+n:])
+		for x := t.cols - n; x < t.cols; x++ {
+			row[x] = ' '
+		}
+	case '@':
+		row := t.cells[t.cursorY]
+		n := min(arg(0, 1), t.cols-t.cursorX)
+		copy(row[t.cursorX+n:], row[t.cursorX:t.cols-n])
+		for x := t.cursorX; x < t.cursorX+n; x++ {
+			row[x] = ' '
+		}
+	case 'X':
+		row := t.cells[t.cursorY]
+		for x := t.cursorX; x < t.cursorX+arg(0, 1) && x < t.cols; x++ {
+			row[x] = ' '
+		}
+	case 'r':
+		top, bottom := arg(0, 1)-1, arg(1, t.rows)-1
+		if top < bottom && bottom < t.rows {
+			t.scrollTop, t.scrollBottom = top, bottom
+			t.cursorX, t.cursorY = 0, 0
+		}
+	case 's':
+		t.savedX, t.savedY = t.cursorX, t.cursorY
+	case 'u':
+		t.restoreCursor()
+	}
+}
+
+// setPrivateModes handles DEC private mode set/reset (CSI ? n h / CSI ? n l)
+func (t *terminalScreen) setPrivateModes(modes []int, enable bool) {
+	for _, mode := range modes {
+		switch mode {
+		case 1:
+			t.appCursorKeys = enable
+		case 25:
+			t.cursorHidden = !enable
+		case 47, 1047, 1049:
+			if enable && t.primaryCells == nil {
+				if mode == 1049 {
+					t.savedX, t.savedY = t.cursorX, t.cursorY
+				}
+				t.primaryCells = t.cells
+				t.cells = blankGrid(t.cols, t.rows)
+			} else if !enable && t.primaryCells != nil {
+				t.cells = t.primaryCells
+				t.primaryCells = nil
+				if mode == 1049 {
+					t.restoreCursor()
+				}
+			}
+		}
+	}
+}
+
+// put writes a printable rune at the cursor, wrapping at the right margin
+func (t *terminalScreen) put(r rune) {
+	if t.wrapNext {
+		t.cursorX = 0
+		t.lineFeed()
+	}
+	t.cells[t.cursorY][t.cursorX] = r
+	if t.cursorX == t.cols-1 {
+		t.wrapNext = true
+	} else {
+		t.cursorX++
+	}
+}
+
+// lineFeed moves the cursor down, scrolling the region at its bottom margin
+func (t *terminalScreen) lineFeed() {
+	t.wrapNext = false
+	if t.cursorY == t.scrollBottom {
+		t.scrollUpRegion(t.scrollTop, t.scrollBottom, 1)
+	} else if t.cursorY < t.rows-1 {
+		t.cursorY++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling the region at its top margin
+func (t *terminalScreen) reverseIndex() {
+	t.wrapNext = false
+	if t.cursorY == t.scrollTop {
+		t.scrollDownRegion(t.scrollTop, t.scrollBottom, 1)
+	} else if t.cursorY > 0 {
+		t.cursorY--
+	}
+}
+
+// scrollUpRegion shifts rows top..bottom up by n, blanking the bottom rows
+func (t *terminalScreen) scrollUpRegion(top, bottom, n int) {
+	n = min(n, bottom-top+1)
+	copy(t.cells[top:bottom+1], t.cells[top+n:bottom+1])
+	for y := bottom - n + 1; y <= bottom; y++ {
+		t.cells[y] = blankLine(t.cols)
+	}
+}
+
+// scrollDownRegion shifts rows top..bottom down by n, blanking the top rows
+func (t *terminalScreen) scrollDownRegion(top, bottom, n int) {
+	n = min(n, bottom-top+1)
+	copy(t.cells[top+n:bottom+1], t.cells[top:bottom+1-n])
+	for y := top; y < top+n; y++ {
+		t.cells[y] = blankLine(t.cols)
+	}
+}
+
+// eraseDisplay implements ED (0: to end, 1: to start, 2/3: whole screen)
+func (t *terminalScreen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		t.eraseLine(0)
+		for y := t.cursorY + 1; y < t.rows; y++ {
+			t.cells[y] = blankLine(t.cols)
+		}
+	case 1:
+		t.eraseLine(1)
+		for y := 0; y < t.cursorY; y++ {
+			t.cells[y] = blankLine(t.cols)
+		}
+	case 2, 3:
+		t.cells = blankGrid(t.cols, t.rows)
+	}
+}
+
+// eraseLine implements EL (0: to end, 1: to start, 2: whole line)
+func (t *terminalScreen) eraseLine(mode int) {
+	row := t.cells[t.cursorY]
+	start, end := 0, t.cols
+	switch mode {
+	case 0:
+		start = t.cursorX
+	case 1:
+		end = t.cursorX + 1
+	}
+	for x := start; x < end; x++ {
+		row[x] = ' '
+	}
+}
+
+// updateSearchResults updates the search results based on current input
+func (m *Model) updateSearchResults() {
+	m.searchResults = []searchResult{}
+	m.searchCursor = 0
+
+	query := strings.ToLower(m.searchInput)
+
+	// Search through containers
+	for _, c := range m.containers {
+		// Check if container matches the query (search name, ID, image, and ports)
+		nameLower := strings.ToLower(c.Name)
+		imageLower := strings.ToLower(c.Image)
+		idLower := strings.ToLower(c.ID)
+		portsLower := strings.ToLower(strings.Join(c.Ports, " "))
+
+		if query == "" || strings.Contains(nameLower, query) ||
+			strings.Contains(imageLower, query) || strings.Contains(idLower, query) ||
+			strings.Contains(portsLower, query) {
+			// Build description with ports if available
+			portsStr := strings.Join(c.Ports, ", ")
+			if portsStr == "" {
+				portsStr = "no ports"
+			}
+			m.searchResults = append(m.searchResults, searchResult{
+				resultType:  "container",
+				display:     c.Name,
+				description: fmt.Sprintf("%s | %s | %s | %s", c.ID, c.Image, portsStr, c.State),
+				containerID: c.ID,
+			})
+		}
+	}
+
+	// Add available commands that match the query
+	commands := []struct {
+		key         string
+		name        string
+		description string
+	}{
+		{"s", "Start", "Start the selected container"},
+		{"t", "Stop", "Stop the selected container"},
+		{"R", "Restart", "Restart the selected container"},
+		{"d", "Destroy", "Permanently remove the selected container"},
+		{"i", "Inspect", "View container details"},
+		{"l", "Logs", "View container logs"},
+		{"e", "Shell", "Open shell in container"},
+		{"o", "Browser", "Open container port in browser"},
+		{"h", "Toggle K8s", "Show/hide Kubernetes containers"},
+		{"a", "Toggle Exited", "Show/hide exited containers"},
+		{"z", "Toggle Paused", "Show/hide paused containers"},
+		{"p", "Pause/Unpause", "Pause or unpause the selected container"},
+		{"K", "Kill", "Send a signal (SIGKILL, SIGTERM, SIGHUP, ...) to the selected container"},
+		{"c", "Copy files", "Copy files into or out of the selected container"},
+		{"b", "Browse files", "Browse, preview, download and upload files in the selected container"},
+		{"D", "Diff", "Show files added, changed or deleted compared to the image"},
+		{"T", "Processes", "Show the processes running in the selected container (top)"},
+		{"r", "Refresh", "Refresh container list"},
+		{"I", "Images", "Manage images (remove, tag, inspect)"},
+		{"V", "Volumes", "Manage volumes (remove, prune)"},
+		{"N", "Networks", "Manage networks and container attachments"},
+		{"U", "Disk usage", "Show reclaimable space and prune"},
+		{"P", "Pull image", "Pull an image with live progress"},
+		{"n", "Run container", "Create and start a container from a form"},
+		{"g", "Run command", "Show the docker run command for the selected container"},
+		{"E", "Export compose", "Write the selected containers to a docker-compose.yml"},
+		{"C", "Recreate", "Recreate the selected container with an edited configuration"},
+	}
+
+	for _, cmd := range commands {
+		cmdLower := strings.ToLower(cmd.name)
+		descLower := strings.ToLower(cmd.description)
+
+		if query == "" || strings.Contains(cmdLower, query) || strings.Contains(descLower, query) {
+			m.searchResults = append(m.searchResults, searchResult{
+				resultType:  "command",
+				display:     fmt.Sprintf("[%s] %s", cmd.key, cmd.name),
+				description: cmd.description,
+				command:     cmd.key,
+			})
+		}
+	}
+}
+
+// executeSearchCommand executes a command from the search results
+func (m *Model) executeSearchCommand(command string) tea.Cmd {
+	switch command {
+	case "s":
+		m.statusMsg = "Starting container..."
+		return m.startContainer()
+	case "t":
+		m.statusMsg = "Stopping container..."
+		return m.stopContainer()
+	case "R":
+		m.statusMsg = "Restarting container..."
+		return m.restartContainer()
+	case "d":
+		// Destroy container - show confirmation dialog
+		m.confirmDestroy()
+		return nil
+	case "i":
+		m.statusMsg = "Loading inspection data..."
+		return m.inspectContainer
+	case "l":
+		m.statusMsg = "Loading logs..."
+		return m.viewContainerLogs()
+	case "e":
+		if len(m.containers) > 0 {
+			containerName := m.containers[m.cursor].Name
+			m.statusMsg = fmt.Sprintf("Opening shell in %s...", containerName)
+			return m.shellIntoContainer()
+		}
+	case "o":
+		if len(m.containers) > 0 {
+			m.statusMsg = "Opening browser..."
+			return m.openBrowserForContainer()
+		}
+	case "h", "a", "z":
+		return m.toggleFilter(command)
+	case "p":
+		return m.togglePause()
+	case "K":
+		m.openKillPicker()
+		return nil
+	case "c":
+		m.openCopyPrompt()
+		return nil
+	case "b":
+		return m.openFiles()
+	case "D":
+		return m.openDiff()
+	case "T":
+		return m.openTop()
+	case "r":
+		m.loading = true
+		m.statusMsg = ""
+		return m.loadContainers(true)
+	case "I":
+		return m.openImages()
+	case "V":
+		return m.openVolumes()
+	case "N":
+		return m.openNetworks()
+	case "U":
+		return m.openDiskUsage()
+	case "P":
+		m.openPull("")
+		return nil
+	case "n":
+		m.openRunForm("")
+		return nil
+	case "g":
+		m.statusMsg = "Generating docker run command..."
+		return m.generateRunCommand()
+	case "E":
+		m.openExportPrompt()
+		return nil
+	case "C":
+		m.statusMsg = "Loading configuration..."
+		return m.loadRecreate()
+	}
+	return nil
+}
+
+// enablePasteCmd returns a command to enable bracketed paste support.
+//
+// Bracketed paste mode allows the application to distinguish between typed text
+// and pasted text. When enabled, pasted content is received as KeyMsg events with
+// the Paste field set to true, and the pasted text available in the Runes field.
+//
+// This is automatically disabled when the program exits.
+//
+// Integration points:
+//   - Called in Init() to enable paste support at program startup
+//   - Paste events are handled in Update() via KeyMsg with Paste=true
+//   - Supported in shell view (appends to command input) and search view (appends to search query)
+func enablePasteCmd() tea.Msg {
+	return tea.EnableBracketedPaste()
+}
+
+// Init is called when the program starts
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(
+		m.subscribeEvents, // Subscribe to container events (triggers the initial load)
+		enablePasteCmd,    // Enable clipboard paste support
+	)
+}
+
+// Update handles messages and updates the model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.currentView == viewShell && m.shellScreen != nil {
+			// Keep the TTY size in step with the popup
+			cols, rows := shellTermSize(m.width, m.height)
+			m.shellScreen.Resize(cols, rows)
+			if m.shellExecID != "" {
+				return m, m.resizeShell(cols, rows)
+			}
+		}
+		return m, nil
+	case tea.KeyMsg:
+		// Handle clipboard paste events (bracketed paste mode)
+		//
+		// Bracketed paste is enabled via tea.EnableBracketedPaste() in Init().
+		// When users paste content (Cmd+V/Ctrl+V), the terminal sends the entire
+		// pasted text as a single KeyMsg with:
+		//   - Paste field = true (indicates this is a paste operation)
+		//   - Runes field = the complete pasted text as []rune
+		//
+		// This allows us to distinguish between typed input and pasted content,
+		// and handle multi-line or large pastes efficiently in one operation.
+		//
+		// Paste handling by view:
+		//   - viewShell: Forwarded to the shell's TTY (buffered until the session is attached)
+		//   - viewSearch: Appends to search query and updates results (useful for searching container names/IDs)
+		//   - Other views: Paste events are ignored
+		//
+		// Note: To copy text FROM the terminal, users should hold Shift while
+		// selecting text with the mouse, then use Cmd+C (Mac) or Ctrl+Shift+C (Linux).
+		if msg.Paste {
+			pastedText := string(msg.Runes)
+			switch m.currentView {
+			case viewShell:
+				// In shell view, send pasted text to the shell
+				m.sendShellInput([]byte(pastedText))
+				return m, nil
+			case viewSearch:
+				// In search view, append pasted text to search input and update results
+				m.searchInput += pastedText
+				m.updateSearchResults()
+				return m, nil
+			}
+			// For other views, ignore paste events
+			return m, nil
+		}
+		// Handle different views
+		switch m.currentView {
+		case viewInspect:
+			return m, m.handleInspectKey(msg)
+		case viewLogs:
+			if m.logsPrompt != "" {
+				return m, m.handleLogsPromptKey(msg)
+			}
+			switch msg.String() {
+			case "esc", "q":
+				// Stop following and go back
+				m.stopLogs()
+				m.currentView = viewList
+			case "p":
+				// Pause/resume consuming the stream
+				m.logsPaused = !m.logsPaused
+				return m, m.waitForLogs()
+			case "up", "k":
+				m.scrollLogs(-1)
+			case "down", "j":
+				m.scrollLogs(1)
+			case "pgup", "b":
+				m.scrollLogs(-m.logsPageHeight())
+			case "pgdown", "f", " ":
+				m.scrollLogs(m.logsPageHeight())
+			case "g", "home":
+				m.logsFollow = false
+				if m.logsBuffer != nil {
+					m.logsTop = m.logsBuffer.Dropped()
+				}
+			case "G", "end":
+				m.logsFollow = true
+			case "w":
+				m.logsWrap = !m.logsWrap
+			case "/":
+				m.logsPrompt = "search"
+				m.logsPromptInput = ""
+			case "n":
+				m.jumpToLogMatch(true)
+			case "N":
+				m.jumpToLogMatch(false)
+			case "t":
+				m.logsPrompt = "tail"
+				m.logsPromptInput = m.logsTail
+			case "s":
+				m.logsPrompt = "since"
+				m.logsPromptInput = m.logsSince
+			case "o":
+				// Cycle which output streams are shown
+				m.logsStreams = (m.logsStreams + 1) % 3
+			case "T":
+				// Timestamps are added by the daemon, so the stream restarts.
+				// The merged view always shows them.
+				if m.logsMerged() {
+					m.statusMsg = "Timestamps are always shown when merging logs"
+					return m, nil
+				}
+				m.logsTimestamps = !m.logsTimestamps
+				return m, m.openLogs(m.logsSources)
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				// Toggle an individual source in the merged view
+				i := int(msg.String()[0] - '1')
+				if m.logsMerged() && i < len(m.logsSources) {
+					m.logsSources[i].hidden = !m.logsSources[i].hidden
+				}
+			}
+		case viewImages:
+			return m, m.handleImagesKey(msg)
+		case viewVolumes:
+			return m, m.handleVolumesKey(msg)
+		case viewNetworks:
+			return m, m.handleNetworksKey(msg)
+		case viewDiskUsage:
+			return m, m.handleDiskUsageKey(msg)
+		case viewPull:
+			return m, m.handlePullKey(msg)
+		case viewRun:
+			return m, m.handleRunKey(msg)
+		case viewFiles:
+			return m, m.handleFilesKey(msg)
+		case viewDiff:
+			return m, m.handleDiffKey(msg)
+		case viewTop:
+			return m, m.handleTopKey(msg)
+		case viewRunCommand:
+			switch msg.String() {
+			case "esc", "q":
+				m.currentView = viewList
+				m.statusMsg = ""
+			case "c", "y":
+				return m, copyToClipboard(formatCommand(m.runCommand.dockerRunArgs()))
+			}
+			return m, nil
+		case viewStats:
+			switch msg.String() {
+			case "esc", "q":
+				m.closeStatsView()
+				m.currentView = viewList
+			}
+		case viewShell:
+			// In shell view every key goes to the TTY (including ESC and
+			// Ctrl+C), so Ctrl+] is reserved for leaving the popup
+			if msg.String() == "ctrl+]" {
+				var hangUp tea.Cmd
+				if m.shellExecID != "" {
+					hangUp = m.hangUpShell(m.shellContainerID, m.shellExecID)
+				}
+				m.closeShell()
+				return m, hangUp
+			}
+			appCursorKeys := m.shellScreen != nil && m.shellScreen.appCursorKeys
+			m.sendShellInput(shellKeyBytes(msg, appCursorKeys))
+		case viewSearch:
+			// In search view, handle search input
+			switch msg.String() {
+			case "esc":
+				// Exit search view
+				m.currentView = viewList
+				m.searchInput = ""
+				m.searchResults = nil
+				m.searchCursor = 0
+			case "enter":
+				// Select the current result
+				if len(m.searchResults) > 0 && m.searchCursor < len(m.searchResults) {
+					result := m.searchResults[m.searchCursor]
+					m.currentView = viewList
+					m.searchInput = ""
+					m.searchResults = nil
+
+					if result.resultType == "container" {
+						// Find and select the container, expanding its project
+						for i, c := range m.containers {
+							if c.ID == result.containerID {
+								m.cursor = i
+								m.cursorProject = ""
+								delete(m.collapsed, c.Project)
+								break
+							}
+						}
+					} else if result.resultType == "command" {
+						// Execute the command
+						return m, m.executeSearchCommand(result.command)
+					}
+				}
+			case "up":
+				if m.searchCursor > 0 {
+					m.searchCursor--
+				}
+			case "down":
+				if m.searchCursor < len(m.searchResults)-1 {
+					m.searchCursor++
+				}
+			case "backspace":
+				if len(m.searchInput) > 0 {
+					m.searchInput = m.searchInput[:len(m.searchInput)-1]
+					m.updateSearchResults()
+				}
+			default:
+				// Add character to search input
+				if len(msg.String()) == 1 {
+					m.searchInput += msg.String()
+					m.updateSearchResults()
+				}
+			}
+		case viewList:
+			// Handle confirmation dialog for destroy operation
+			if m.confirmingDestroy {
+				switch msg.String() {
+				case "y", "Y":
+					// User confirmed - destroy the container
+					m.confirmingDestroy = false
+					m.statusMsg = "Destroying container..."
+					return m, m.destroyContainer()
+				case "n", "N", "esc":
+					// User cancelled - clear confirmation state
+					m.confirmingDestroy = false
+					m.containerToDestroy = ""
+					m.destroyTargets = nil
+					m.statusMsg = "Destroy cancelled"
+					return m, clearStatusAfterDelay(2 * time.Second)
+				}
+				// Ignore other keys during confirmation
+				return m, nil
+			}
+
+			if m.selectPrompt {
+				return m, m.handleSelectPromptKey(msg)
+			}
+			if m.exportPrompt || m.confirmingExportOverwrite {
+				return m, m.handleExportKey(msg)
+			}
+			if m.killPicker {
+				return m, m.handleKillPickerKey(msg)
+			}
+			if m.copyStage != copyStageNone {
+				return m, m.handleCopyKey(msg)
+			}
+
+			// Actions that need a single container don't apply to a project header
+			if m.cursorProject != "" {
+				switch msg.String() {
+				case "i", "e", "x", "o", "m", "g", "C", "c", "b", "D", "T":
+					m.statusMsg = fmt.Sprintf("Move to a container in %s to use this action", m.cursorProject)
+					return m, clearStatusAfterDelay(2 * time.Second)
+				}
+			}
+
+			// In list view, handle all navigation and actions
+			switch msg.String() {
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case "up", "k":
+				m.moveCursor(-1)
+			case "down", "j":
+				m.moveCursor(1)
+			case " ":
+				// Toggle selection of the container (or whole project) under the cursor
+				if targets := m.cursorTargets(); len(targets) > 0 {
+					allSelected := true
+					for _, c := range targets {
+						allSelected = allSelected && m.selected[c.ID]
+					}
+					for _, c := range targets {
+						if m.selected[c.ID] == allSelected {
+							m.toggleSelected(c.ID)
+						}
+					}
+					m.statusMsg = fmt.Sprintf("%d selected", len(m.selectedContainers()))
+				}
+			case "A":
+				// Select every shown container, or clear if all are selected
+				if len(m.selectedContainers()) == len(m.containers) {
+					m.selected = nil
+				} else {
+					for _, c := range m.containers {
+						if !m.selected[c.ID] {
+							m.toggleSelected(c.ID)
+						}
+					}
+				}
+				m.statusMsg = fmt.Sprintf("%d selected", len(m.selectedContainers()))
+			case "F":
+				// Select containers matching a filter
+				m.selectPrompt = true
+				m.selectPromptInput = ""
+			case "esc":
+				// Clear the selection
+				if len(m.selected) > 0 {
+					m.selected = nil
+					m.statusMsg = "Selection cleared"
+					return m, clearStatusAfterDelay(2 * time.Second)
+				}
+			case "enter":
+				// Collapse or expand the project under the cursor
+				if m.cursorProject != "" {
+					m.toggleCollapsed(m.cursorProject)
+				}
+			case "G":
+				// Toggle grouping by compose project
+				m.groupByProject = !m.groupByProject
+				m.filterContainers()
+				if m.groupByProject {
+					m.statusMsg = "Grouping by compose project"
+				} else {
+					m.statusMsg = "Ungrouped"
+				}
+				return m, clearStatusAfterDelay(3 * time.Second)
+			case "r", "f5":
+				// Refresh containers
+				m.loading = true
+				m.statusMsg = ""
+				return m, m.loadContainers(true) // Show refresh message
+			case "s":
+				// Start container
+				m.statusMsg = "Starting container..."
+				return m, m.startContainer()
+			case "t":
+				// Stop container
+				m.statusMsg = "Stopping container..."
+				return m, m.stopContainer()
+			case "R":
+				// Restart container (capital R)
+				m.statusMsg = "Restarting container..."
+				return m, m.restartContainer()
+			case "i":
+				// Inspect container
+				m.statusMsg = "Loading inspection data..."
+				return m, m.inspectContainer
+			case "g":
+				// Generate the docker run command for the container
+				m.statusMsg = "Generating docker run command..."
+				return m, m.generateRunCommand()
+			case "E":
+				// Export the selected containers as a compose file
+				m.openExportPrompt()
+				return m, nil
+			case "C":
+				// Recreate the container with an edited configuration
+				m.statusMsg = "Loading configuration..."
+				return m, m.loadRecreate()
+			case "l":
+				// View logs
+				m.statusMsg = "Loading logs..."
+				return m, m.viewContainerLogs()
+			case "e", "x":
+				// Shell into container
+				if len(m.containers) > 0 {
+					containerName := m.containers[m.cursor].Name
+					m.statusMsg = fmt.Sprintf("Opening shell in %s...", containerName)
+					return m, m.shellIntoContainer()
+				}
+			case "o":
+				// Open browser for container port
+				if len(m.containers) > 0 {
+					m.statusMsg = "Opening browser..."
+					return m, m.openBrowserForContainer()
+				}
+			case "d":
+				// Destroy containers - show confirmation dialog
+				m.confirmDestroy()
+			case "h", "a", "z":
+				// Toggle hiding k8s, exited or paused containers
+				return m, m.toggleFilter(msg.String())
+			case "p":
+				// Pause or unpause containers
+				return m, m.togglePause()
+			case "K":
+				// Kill containers with a chosen signal
+				m.openKillPicker()
+				return m, nil
+			case "c":
+				// Copy files into or out of the container
+				m.openCopyPrompt()
+				return m, nil
+			case "b":
+				// Browse the container's filesystem
+				return m, m.openFiles()
+			case "D":
+				// Show what changed compared to the image
+				return m, m.openDiff()
+			case "T":
+				// Show the container's processes
+				return m, m.openTop()
+			case "I":
+				// Open the images view
+				return m, m.openImages()
+			case "V":
+				// Open the volumes view
+				return m, m.openVolumes()
+			case "N":
+				// Open the networks view for the selected container
+				return m, m.openNetworks()
+			case "U":
+				// Open the disk usage view
+				return m, m.openDiskUsage()
+			case "P":
+				// Pull an image
+				m.openPull("")
+				return m, nil
+			case "n":
+				// Create and run a new container
+				m.openRunForm("")
+				return m, nil
+			case "m":
+				// Open the stats view with graphs for the selected container
+				return m, m.openStatsView()
+			case "M":
+				// Toggle the CPU/memory/network/block I/O columns
+				cmd := m.toggleStats()
+				if m.showStats {
+					m.statusMsg = "Showing resource stats"
+				} else {
+					m.statusMsg = "Hiding resource stats"
+				}
+				return m, tea.Batch(cmd, clearStatusAfterDelay(3*time.Second))
+			case "S":
+				// Cycle the stats column the list is sorted by
+				var cmd tea.Cmd
+				if !m.showStats {
+					cmd = m.toggleStats()
+				}
+				m.statsSort = (m.statsSort + 1) % (statsSortBlock + 1)
+				m.filterContainers()
+				m.statusMsg = "Sorted by " + m.statsSort.String()
+				return m, tea.Batch(cmd, clearStatusAfterDelay(3*time.Second))
+			case "/":
+				// Open fuzzy search
+				m.currentView = viewSearch
+				m.searchInput = ""
+				m.searchCursor = 0
+				m.updateSearchResults() // Initialize with all results
+			}
+		}
+	case containersLoadedMsg:
+		if msg.err == nil && msg.seq < m.listSeq {
+			// A list requested later has already been applied
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.reconcileContainers(msg.containers, msg.seq)
+			m.filterContainers()
+			statsCmd := m.syncStatsStreams()
+			if msg.showRefresh {
+				m.statusMsg = "Containers refreshed"
+				// Clear status after 2 seconds
+				return m, tea.Batch(statsCmd, clearStatusAfterDelay(2*time.Second))
+			} else if m.statusMsg == "" {
+				// Only set container count if no status message exists (initial load)
+				m.statusMsg = containerCountMsg(len(m.containers))
+			}
+			// Otherwise preserve existing status message (for background refresh)
+			return m, statsCmd
+		}
+	case operationCompleteMsg:
+		m.statusMsg = msg.message
+		if msg.success && !m.eventsActive {
+			// Refresh container list after successful operation (don't show refresh message).
+			// When the events stream is connected it delivers the change instead.
+			m.loading = true
+			return m, m.loadContainers(false)
+		}
+	case bulkResultMsg:
+		if m.bulkOp == nil || msg.opID != m.bulkOp.id {
+			return m, nil
+		}
+		m.bulkOp.results[msg.id] = msg.err
+		m.statusMsg = m.bulkOp.summary()
+		if !m.bulkOp.done() {
+			return m, nil
+		}
+		succeeded := false
+		for _, err := range m.bulkOp.results {
+			succeeded = succeeded || err == nil
+		}
+		m.bulkOp = nil
+		if succeeded && !m.eventsActive {
+			m.loading = true
+			return m, m.loadContainers(false)
+		}
+	case clearStatusMsg:
+		// Clear status message and show standard status
+		m.statusMsg = containerCountMsg(len(m.containers))
+	case eventsSubscribedMsg:
+		// Resync once to pick up anything that changed while unsubscribed
+		m.eventsCh = msg.messages
+		m.eventsErrCh = msg.errs
+		m.eventsActive = true
+		return m, tea.Batch(
+			m.loadContainers(false),
+			waitForEvent(m.eventsCh, m.eventsErrCh),
+		)
+	case containerEventMsg:
+		return m, tea.Batch(
+			m.applyContainerEvent(msg.event),
+			waitForEvent(m.eventsCh, m.eventsErrCh),
+		)
+	case eventsStreamErrMsg:
+		// Stream dropped - fall back to periodic full resyncs until it reconnects
+		m.eventsActive = false
+		m.eventsCh = nil
+		m.eventsErrCh = nil
+		m.statusMsg = fmt.Sprintf("Event stream lost (%v), resyncing every %s", msg.err, resyncInterval)
+		return m, tickCmd()
+	case containerUpdatedMsg:
+		if msg.seq != m.containerSeq[msg.id] {
+			// A later event for the container has its own fetch in flight,
+			// or a newer full list already covers it
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else if msg.info == nil {
+			m.removeContainer(msg.id)
+		} else {
+			m.upsertContainer(*msg.info)
+		}
+		return m, m.syncStatsStreams()
+	case statsMsg:
+		if msg.stream.detail {
+			// A stale view stream's channel no longer has a reader
+			if msg.stream != m.statsViewStream {
+				return m, nil
+			}
+			m.statsHistory = append(m.statsHistory, msg.sample)
+			if len(m.statsHistory) > statsHistoryLen {
+				m.statsHistory = m.statsHistory[len(m.statsHistory)-statsHistoryLen:]
+			}
+			return m, waitForStats(m.statsViewCh)
+		}
+		if m.statsStreams[msg.id] == msg.stream {
+			m.stats[msg.id] = msg.sample
+			m.sortContainers()
+		}
+		return m, waitForStats(m.statsCh)
+	case statsEndedMsg:
+		if msg.stream.detail {
+			if msg.stream == m.statsViewStream {
+				m.statsViewEnded = true
+				if msg.err != nil {
+					m.statusMsg = fmt.Sprintf("Stats error: %v", msg.err)
+				}
+			}
+			return m, nil
+		}
+		// The stream closes when the container stops; the next container
+		// update reopens it if the container is running again
+		if m.statsStreams[msg.id] == msg.stream {
+			delete(m.statsStreams, msg.id)
+			delete(m.stats, msg.id)
+			if msg.err != nil {
+				m.statusMsg = fmt.Sprintf("Stats error for %s: %v", msg.id, msg.err)
+			}
+		}
+		return m, waitForStats(m.statsCh)
+	case tickMsg:
+		// Try to reopen the events stream; eventsSubscribedMsg then does the
+		// full resync in background. If it drops again the next
+		// eventsStreamErrMsg schedules another tick.
+		if m.eventsActive {
+			return m, nil
+		}
+		return m, m.subscribeEvents
+	case runCommandMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.runCommandName = msg.name
+			m.runCommand = msg.command
+			m.currentView = viewRunCommand
+			m.statusMsg = ""
+		}
+	case composeExportMsg:
+		m.statusMsg = msg.message
+		m.exportTargets = nil
+		return m, clearStatusAfterDelay(5 * time.Second)
+	case copyResultMsg:
+		m.statusMsg = msg.message
+		return m, clearStatusAfterDelay(2 * time.Second)
+	case inspectDataMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.inspectData = msg.data
+			m.inspectInfo = msg.info
+			m.inspectTitle = msg.title
+			m.inspectReturn = msg.returnTo
+			m.inspectTab, m.inspectOffset = 0, 0
+			m.inspectQuery, m.inspectQueryLines, m.inspectQueryPrompt = "", nil, false
+			m.currentView = viewInspect
+			m.statusMsg = ""
+		}
+	case imagesLoadedMsg:
+		m.imagesLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.images = msg.images
+			if m.imagesCursor >= len(m.images) {
+				m.imagesCursor = max(len(m.images)-1, 0)
+			}
+		}
+	case volumesLoadedMsg:
+		m.volumesLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			// Apply sizes DiskUsage already returned
+			m.volumes = msg.volumes
+			m.applyVolumeSizes()
+			if m.volumesCursor >= len(m.volumes) {
+				m.volumesCursor = max(len(m.volumes)-1, 0)
+			}
+		}
+	case volumeSizesMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error getting volume sizes: %v", msg.err)
+		} else {
+			m.volumeSizes = msg.sizes
+			m.applyVolumeSizes()
+		}
+	case volumeOperationMsg:
+		m.statusMsg = msg.message
+		m.volumeToRemove = ""
+		if msg.success {
+			m.volumesLoading = true
+			return m, tea.Batch(m.loadVolumes(), m.loadVolumeSizes(), clearStatusAfterDelay(3*time.Second))
+		}
+	case copyStatMsg:
+		if m.copyStage != copyStageContainerPath {
+			return m, nil
+		}
+		switch {
+		case msg.err != nil:
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		case m.copyInto && !msg.stat.Mode.IsDir():
+			m.statusMsg = fmt.Sprintf("Error: %s is not a directory in the container", msg.path)
+		default:
+			m.copyContainerPath = msg.path
+			m.copyStat = msg.stat
+			m.copyStage = copyStageLocalPath
+			m.copyInput = "."
+			if m.copyInto {
+				m.copyInput = ""
+			}
+			m.statusMsg = ""
+		}
+	case copyProgressMsg:
+		if msg.id != m.copyID {
+			return m, nil
+		}
+		m.statusMsg = fmt.Sprintf("Copying %s: %s", m.copyLabel, formatBytes(float64(msg.done), false))
+		if msg.total > 0 {
+			m.statusMsg += fmt.Sprintf(" / %s (%d%%)", formatBytes(float64(msg.total), false), min(msg.done*100/msg.total, 100))
+		}
+		return m, waitForCopy(m.copyCh)
+	case copyDoneMsg:
+		if msg.id != m.copyID {
+			return m, nil
+		}
+		m.copyRunning = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Failed to copy %s: %v", m.copyLabel, msg.err)
+			return m, clearStatusAfterDelay(5 * time.Second)
+		}
+		m.statusMsg = fmt.Sprintf("Copied %s %s", m.copyLabel, msg.message)
+		if m.currentView == viewFiles && m.copyInto {
+			// Show the uploaded files
+			m.filesLoading = true
+			m.filesID++
+			return m, tea.Batch(m.loadFiles(m.filesPath), clearStatusAfterDelay(5*time.Second))
+		}
+		return m, clearStatusAfterDelay(5 * time.Second)
+	case filesListedMsg:
+		if msg.id != m.filesID || m.currentView != viewFiles {
+			return m, nil
+		}
+		m.filesLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		// A refresh keeps the cursor, and returning to the parent selects
+		// the directory just left
+		previous, cursor := m.filesPath, m.filesCursor
+		m.filesCursor = 0
+		switch {
+		case msg.path == previous:
+			m.filesCursor = min(cursor, max(len(msg.entries)-1, 0))
+		case path.Dir(previous) == msg.path:
+			for i, e := range msg.entries {
+				if e.Name == path.Base(previous) {
+					m.filesCursor = i
+				}
+			}
+		}
+		m.filesPath = msg.path
+		m.filesEntries = msg.entries
+		m.filesPreviewing = false
+		m.statusMsg = ""
+	case topLoadedMsg:
+		if msg.id != m.topID || m.currentView != viewTop {
+			return m, nil
+		}
+		m.topLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.topTitles = msg.titles
+			m.topProcesses = msg.processes
+			sortTopProcesses(m.topTitles, m.topProcesses, m.topSort)
+			m.followTopPID()
+		}
+		return m, topTick(m.topID)
+	case topTickMsg:
+		if msg.id != m.topID || m.currentView != viewTop {
+			return m, nil
+		}
+		return m, m.loadTop()
+	case topSignalMsg:
+		m.statusMsg = msg.message
+		return m, clearStatusAfterDelay(5 * time.Second)
+	case diffLoadedMsg:
+		if msg.id != m.diffID || m.currentView != viewDiff {
+			return m, nil
+		}
+		m.diffLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		// A refresh keeps what was expanded
+		keep := m.diffRoot != nil
+		m.diffRoot = msg.root
+		if !keep {
+			m.collapseLargeDiffDirs()
+		}
+		m.diffCursor = min(m.diffCursor, max(len(m.diffRows())-1, 0))
+	case filesPreviewMsg:
+		if msg.id != m.filesID || m.currentView != viewFiles {
+			return m, nil
+		}
+		m.filesLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.filesPreviewing = true
+		m.filesPreviewPath = msg.path
+		m.filesPreviewLines = msg.lines
+		m.filesPreviewNote = msg.note
+		m.filesPreviewOffset = 0
+		m.statusMsg = ""
+	case recreateLoadedMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.openRecreateForm(msg.target)
+		}
+	case runResultMsg:
+		m.runSubmitting = false
+		m.statusMsg = msg.message
+		if msg.created {
+			m.currentView = m.runReturn
+			var cmds []tea.Cmd
+			if m.runReturn == viewImages {
+				// The image now has a container using it
+				m.imagesLoading = true
+				cmds = append(cmds, m.loadImages())
+			}
+			if !m.eventsActive {
+				m.loading = true
+				cmds = append(cmds, m.loadContainers(false))
+			}
+			return m, tea.Batch(cmds...)
+		}
+	case pullMsg:
+		if msg.id != m.pullID {
+			return m, nil
+		}
+		m.applyPullMessage(msg.message)
+		return m, waitForPull(m.pullCh)
+	case pullEndedMsg:
+		if msg.id != m.pullID {
+			return m, nil
+		}
+		m.pullRunning = false
+		m.pullCancel = nil
+		m.pullErr = msg.err
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+			m.statusMsg = "Pull cancelled"
+		case msg.err != nil:
+			m.statusMsg = fmt.Sprintf("Failed to pull %s: %v", m.pullRef, msg.err)
+		default:
+			m.statusMsg = fmt.Sprintf("Pulled %s", m.pullRef)
+		}
+	case diskUsageMsg:
+		m.diskLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.diskUsage = msg.rows
+			if m.diskCursor >= len(m.diskUsage) {
+				m.diskCursor = max(len(m.diskUsage)-1, 0)
+			}
+		}
+	case diskPruneMsg:
+		m.statusMsg = msg.message
+		if msg.success {
+			m.diskLoading = true
+			return m, tea.Batch(m.loadDiskUsage(), clearStatusAfterDelay(3*time.Second))
+		}
+	case networksLoadedMsg:
+		m.networksLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.networks = msg.networks
+			if m.networksCursor >= len(m.networks) {
+				m.networksCursor = max(len(m.networks)-1, 0)
+			}
+		}
+	case networkOperationMsg:
+		m.statusMsg = msg.message
+		m.networkToRemove = ""
+		if msg.success {
+			// Attachments come from the container list, and network events
+			// aren't subscribed to, so reload both
+			m.networksLoading = true
+			return m, tea.Batch(m.loadNetworks(), m.loadContainers(false), clearStatusAfterDelay(3*time.Second))
+		}
+	case imageOperationMsg:
+		m.statusMsg = msg.message
+		if msg.success {
+			m.imagesLoading = true
+			return m, tea.Batch(m.loadImages(), clearStatusAfterDelay(3*time.Second))
+		}
+	case logsStreamMsg:
+		if msg.streamID != m.logsStreamID {
+			// A newer stream replaced this one (its context is already cancelled)
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+			m.stopLogs()
+		} else {
+			m.logsStream = msg.stream
+			m.logsBuffer = newLogBuffer(maxLogLines)
+			m.currentView = viewLogs
+			m.statusMsg = ""
+			return m, m.waitForLogs()
+		}
+	case logLinesMsg:
+		if msg.streamID == m.logsStreamID && m.logsBuffer != nil {
+			m.logsWaiting = false
+			// Older lines slotted in above the top line push it down; keep
+			// the scrolled view on the same content
+			for _, pos := range m.logsBuffer.Append(msg.lines...) {
+				if pos <= m.logsTop {
+					m.logsTop++
+				}
+			}
+			return m, m.waitForLogs()
+		}
+	case logsStreamEndedMsg:
+		if msg.streamID == m.logsStreamID {
+			m.logsWaiting = false
+			m.logsStream = nil
+			m.logsEnded = true
+			if msg.err != nil && m.currentView == viewLogs {
+				m.statusMsg = fmt.Sprintf("Log stream error: %v", msg.err)
+			}
+		}
+	case shellReadyMsg:
+		if msg.sessionID != m.shellSessionID || m.currentView != viewShell {
+			// User left the popup, or opened another shell, before the
+			// session attached
+			if msg.conn != nil {
+				msg.conn.Close()
+				return m, m.hangUpShell(msg.containerID, msg.execID)
+			}
+		} else if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Failed to create shell: %v", msg.err)
+			m.closeShell()
+		} else {
+			m.shellExecID = msg.execID
+			m.shellConn = msg.conn
+			m.statusMsg = ""
+
+			// Flush anything typed while connecting
+			pending := m.shellInput
+			m.shellInput = ""
+			m.sendShellInput([]byte(pending))
+
+			cols, rows := shellTermSize(m.width, m.height)
+			return m, tea.Batch(
+				readShellOutput(m.shellExecID, m.shellConn),
+				m.resizeShell(cols, rows),
+			)
+		}
+	case shellOutputMsg:
+		if msg.execID == m.shellExecID && m.shellScreen != nil {
+			m.shellScreen.Write(msg.data)
+			return m, readShellOutput(m.shellExecID, m.shellConn)
+		}
+	case shellExitedMsg:
+		if msg.execID == m.shellExecID && m.currentView == viewShell {
+			m.closeShell()
+			m.statusMsg = "Shell session ended"
+			return m, clearStatusAfterDelay(3 * time.Second)
+		}
+	case shellHungUpMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Shell may still be running: %v", msg.err)
+			return m, clearStatusAfterDelay(5 * time.Second)
+		}
+	}
+	return m, nil
+}
+
+// View renders the UI
+func (m Model) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress q to quit.\n", m.err)
+	}
+
+	switch m.currentView {
+	case viewInspect:
+		return m.viewInspectMode()
+	case viewLogs:
+		return m.viewLogsMode()
+	case viewShell:
+		return m.viewShellMode()
+	case viewSearch:
+		return m.viewSearchMode()
+	case viewStats:
+		return m.viewStatsMode()
+	case viewImages:
+		return m.viewImagesMode()
+	case viewVolumes:
+		return m.viewVolumesMode()
+	case viewNetworks:
+		return m.viewNetworksMode()
+	case viewDiskUsage:
+		return m.viewDiskUsageMode()
+	case viewPull:
+		return m.viewPullMode()
+	case viewRun:
+		return m.viewRunMode()
+	case viewRunCommand:
+		return m.viewRunCommandMode()
+	case viewFiles:
+		return m.viewFilesMode()
+	case viewDiff:
+		return m.viewDiffMode()
+	case viewTop:
+		return m.viewTopMode()
+	default:
+		return m.viewListMode()
+	}
+}
+
+// viewListMode renders the container list view
+func (m Model) viewListMode() string {
+	var s strings.Builder
+
+	// Title with styled connection info
+	title := "🐳 Local Container Manager (lcm)"
+	if m.socketPath != "" {
+		// socketPath now contains the platform name directly
+		title += " [Connected to: " + m.socketPath + "]"
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	if m.loading {
+		s.WriteString("Loading containers...\n")
+	} else if len(m.containers) == 0 {
+		s.WriteString("No containers found.\n")
+	} else {
+		// Calculate how many containers we can show
+		// Account for: title(2) + header(1) + divider(1) + scroll/blank(2) + status(2) + help box(11) = 19 lines overhead
+		availableHeight := m.height - 19
+		if availableHeight < 3 {
+			availableHeight = 3 // Minimum
+		}
+
+		// Calculate scroll window over the list rows (containers and,
+		// when grouping, project headers)
+		rows := m.listRows()
+		cursorRow := m.cursorRow(rows)
+		startIdx := 0
+		endIdx := len(rows)
+
+		if len(rows) > availableHeight {
+			// Need to scroll
+			// Keep cursor in the middle when possible
+			half := availableHeight / 2
+			startIdx = cursorRow - half
+			if startIdx < 0 {
+				startIdx = 0
+			}
+			endIdx = startIdx + availableHeight
+			if endIdx > len(rows) {
+				endIdx = len(rows)
+				startIdx = endIdx - availableHeight
+				if startIdx < 0 {
+					startIdx = 0
+				}
+			}
+		}
+
+		// Responsive column layout with STATUS pinned to the right
+		// Fixed widths: ID=12, STATE=8 (covers "running"/"exited"/"created")
+		// Variable widths: NAME, IMAGE, OPENPORTS share remaining space
+		// STATUS: pinned to right edge
+
+		const (
+			idWidth    = 12
+			stateWidth = 8
+			colSpacing = 2  // spaces between columns
+			cursorCol  = 2  // space for cursor indicator
+		)
+
+		// Calculate max content widths for variable columns
+		maxNameLen := len("NAME")
+		maxImageLen := len("IMAGE")
+		maxPortsLen := len("OPENPORTS")
+		maxStatusLen := len("STATUS")
+
+		for i := startIdx; i < endIdx; i++ {
+			if rows[i].container < 0 {
+				continue
+			}
+			c := m.containers[rows[i].container]
+			if len(c.Name) > maxNameLen {
+				maxNameLen = len(c.Name)
+			}
+			if len(c.Image) > maxImageLen {
+				maxImageLen = len(c.Image)
+			}
+			portsStr := strings.Join(c.Ports, ", ")
+			if portsStr == "" {
+				portsStr = "-"
+			}
+			if len(portsStr) > maxPortsLen {
+				maxPortsLen = len(portsStr)
+			}
+			if len(c.Status) > maxStatusLen {
+				maxStatusLen = len(c.Status)
+			}
+		}
+
+		// Calculate available width for variable columns
+		// Layout: [cursor] ID  NAME  IMAGE  OPENPORTS  STATE  [gap]  STATUS
+		fixedWidth := cursorCol + idWidth + stateWidth + (6 * colSpacing) + maxStatusLen
+		if m.showStats {
+			fixedWidth += lipgloss.Width(m.statsHeader())
+		}
+		availableForVariable := m.width - fixedWidth
+
+		// Minimum widths for variable columns
+		minNameWidth := 10
+		minImageWidth := 10
+		minPortsWidth := 9 // "OPENPORTS"
+
+		// Distribute available space among NAME, IMAGE, OPENPORTS
+		// Give proportional space based on content, with minimums
+		totalContentWidth := maxNameLen + maxImageLen + maxPortsLen
+		if totalContentWidth == 0 {
+			totalContentWidth = 1
+		}
+
+		var nameWidth, imageWidth, portsWidth int
+
+		if availableForVariable >= totalContentWidth {
+			// Enough space for all content - distribute extra space proportionally
+			// Start with content widths
+			nameWidth = maxNameLen
+			imageWidth = maxImageLen
+			portsWidth = maxPortsLen
+
+			// Calculate extra space and distribute it
+			extraSpace := availableForVariable - totalContentWidth
+			if extraSpace > 0 {
+				// Distribute extra space: 50% to NAME, 35% to IMAGE, 15% to OPENPORTS
+				// NAME gets more since it's typically the most important identifier
+				nameWidth += (extraSpace * 50) / 100
+				imageWidth += (extraSpace * 35) / 100
+				portsWidth += (extraSpace * 15) / 100
+
+				// Distribute any remaining space due to rounding
+				remaining := availableForVariable - (nameWidth + imageWidth + portsWidth)
+				if remaining > 0 {
+					nameWidth += remaining
+				}
+			}
+		} else if availableForVariable >= minNameWidth+minImageWidth+minPortsWidth {
+			// Constrained: distribute proportionally with minimums
+			nameWidth = max(minNameWidth, availableForVariable*maxNameLen/totalContentWidth)
+			imageWidth = max(minImageWidth, availableForVariable*maxImageLen/totalContentWidth)
+			portsWidth = max(minPortsWidth, availableForVariable*maxPortsLen/totalContentWidth)
+
+			// Ensure we don't exceed available space
+			for nameWidth+imageWidth+portsWidth > availableForVariable {
+				if imageWidth > minImageWidth {
+					imageWidth--
+				} else if nameWidth > minNameWidth {
+					nameWidth--
+				} else if portsWidth > minPortsWidth {
+					portsWidth--
+				} else {
+					break
+				}
+			}
+		} else {
+			// Very narrow terminal: use minimums
+			nameWidth = minNameWidth
+			imageWidth = minImageWidth
+			portsWidth = minPortsWidth
+		}
+
+		// Header - build left part, then pin STATE and STATUS to right
+		leftHeader := fmt.Sprintf(" %-*s  %-*s  %-*s  %-*s",
+			idWidth, "ID", nameWidth, "NAME", imageWidth, "IMAGE", portsWidth, "OPENPORTS")
+		if m.showStats {
+			leftHeader += m.statsHeader()
+		}
+
+		// STATUS column width (add padding for readability)
+		statusWidth := maxStatusLen + 2
+		if statusWidth < 8 {
+			statusWidth = 8
+		}
+
+		// Right section: STATE + STATUS pinned to right edge
+		rightWidth := stateWidth + 2 + statusWidth // STATE + spacing + STATUS
+
+		// Calculate gap to pin right section to right edge
+		headerGap := m.width - lipgloss.Width(leftHeader) - rightWidth - cursorCol
+		if headerGap < 2 {
+			headerGap = 2
+		}
+
+		// Build header with STATE and STATUS pinned right, both left-justified in their columns
+		rightHeader := fmt.Sprintf("%-*s  %-*s", stateWidth, "STATE", statusWidth, "STATUS")
+		// Ensure headerGap is non-negative
+		if headerGap < 0 {
+			headerGap = 0
+		}
+		headerText := leftHeader + strings.Repeat(" ", headerGap) + rightHeader
+		// Ensure header fills full width
+		if lipgloss.Width(headerText) < m.width {
+			padding := m.width - lipgloss.Width(headerText)
+			if padding > 0 {
+				headerText += strings.Repeat(" ", padding)
+			}
+		}
+		s.WriteString(headerStyle.Render(headerText) + "\n")
+
+		// Full width divider
+		dividerWidth := m.width
+		if dividerWidth < 1 {
+			dividerWidth = 1
+		}
+		s.WriteString(dividerStyle.Render(strings.Repeat("─", dividerWidth)) + "\n")
+
+		// Container list (scrollable window)
+		for i := startIdx; i < endIdx; i++ {
+			if rows[i].container < 0 {
+				header := m.projectHeader(rows[i].project)
+				if i == cursorRow {
+					s.WriteString(selectedStyle.Render(fmt.Sprintf("▶ %-*s", max(m.width-2, 0), header)) + "\n")
+				} else {
+					s.WriteString("  " + titleStyle.UnsetPadding().Render(header) + "\n")
+				}
+				continue
+			}
+			c := m.containers[rows[i].container]
+
+			// Truncate long names and images based on calculated widths
+			name := c.Name
+			if len(name) > nameWidth {
+				name = name[:nameWidth-3] + "..."
+			}
+			image := c.Image
+			if len(image) > imageWidth {
+				image = image[:imageWidth-3] + "..."
+			}
+
+			// Truncate ports if needed
+			portsStr := strings.Join(c.Ports, ", ")
+			if portsStr == "" {
+				portsStr = "-"
+			}
+			if len(portsStr) > portsWidth {
+				portsStr = portsStr[:portsWidth-3] + "..."
+			}
+
+			// Style state based on container status
+			stateText := c.State
+			stateRaw := c.State // Keep unstyled version for width calculation
+			if c.State == "running" {
+				stateText = runningStyle.Render(c.State)
+			} else if c.State == "exited" {
+				stateText = exitedStyle.Render(c.State)
+			} else if c.State == "paused" {
+				stateText = pausedStyle.Render(c.State)
+			}
+
+			// Build left part of line (without cursor)
+			// Selected containers are marked with "*" in the first column
+			mark := " "
+			if m.selected[c.ID] {
+				mark = "*"
+			}
+			leftPart := fmt.Sprintf("%s%-*s  %-*s  %-*s  %-*s",
+				mark, idWidth, c.ID, nameWidth, name, imageWidth, image, portsWidth, portsStr)
+			if m.showStats {
+				leftPart += m.statsCells(c)
+			}
+
+			// Calculate gap to pin STATE and STATUS to right
+			lineGap := m.width - len(leftPart) - rightWidth - cursorCol
+			if lineGap < 2 {
+				lineGap = 2
+			}
+
+			// Build right section with styled STATE and STATUS, both left-justified
+			paddingCount := stateWidth - len(stateRaw)
+			if paddingCount < 0 {
+				paddingCount = 0
+			}
+			statePadding := strings.Repeat(" ", paddingCount)
+			rightPart := stateText + statePadding + "  " + fmt.Sprintf("%-*s", statusWidth, c.Status)
+
+			// Ensure lineGap is non-negative
+			if lineGap < 0 {
+				lineGap = 0
+			}
+			line := leftPart + strings.Repeat(" ", lineGap) + rightPart
+
+			if i == cursorRow {
+				// Highlight selected line - full width
+				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+			} else {
+				s.WriteString("  " + line + "\n")
+			}
+		}
+
+		// Show scroll indicator if needed
+		if len(rows) > availableHeight {
+			noun := "containers"
+			if m.groupByProject {
+				noun = "rows"
+			}
+			s.WriteString(fmt.Sprintf("\nShowing %d-%d of %d %s (scroll with ↑/↓)\n",
+				startIdx+1, endIdx, len(rows), noun))
+		} else {
+			s.WriteString("\n")
+		}
+	}
+
+	// Select-by-filter prompt replaces the status message while open
+	if m.selectPrompt {
+		s.WriteString(statusStyle.Render("Select matching: "+m.selectPromptInput+"█") + "\n\n")
+	} else if m.killPicker {
+		s.WriteString(statusStyle.Render(fmt.Sprintf("Signal for %s: ", pluralize(len(m.killTargets), "container"))) +
+			signalChoices(m.killSignal) + exitedStyle.Render("  (←/→ choose, enter send, esc cancel)") + "\n\n")
+	} else if m.copyStage == copyStageDirection {
+		s.WriteString(statusStyle.Render(m.copyPromptText()) + exitedStyle.Render("  (esc cancels)") + "\n\n")
+	} else if m.copyStage != copyStageNone {
+		s.WriteString(statusStyle.Render(m.copyPromptText()+m.copyInput+"█") + "\n")
+		if m.statusMsg != "" {
+			s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n")
+		}
+		s.WriteString("\n")
+	} else if m.exportPrompt {
+		s.WriteString(statusStyle.Render(fmt.Sprintf("Export %s to: %s█", pluralize(len(m.exportTargets), "container"), m.exportPromptInput)) + "\n\n")
+	} else if m.statusMsg != "" {
+		// Status message - styled
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
+
+	// Help text - styled box with highlighted keys
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  Navigation: %s Up  %s Down  %s Search\n",
+		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("/:"))
+	selection := ""
+	if n := len(m.selectedContainers()); n > 0 {
+		selection = fmt.Sprintf(" (%d selected, actions apply to all)", n)
+	}
+	helpText += fmt.Sprintf("  Select:     %s Toggle  %s All/none  %s By filter  %s Clear%s\n",
+		keyStyle.Render("space:"), keyStyle.Render("A:"), keyStyle.Render("F:"), keyStyle.Render("esc:"), selection)
+	helpText += fmt.Sprintf("  Actions:    %s Start  %s Stop  %s Restart  %s Pause/unpause  %s Kill  %s Shell  %s Browser  %s Destroy\n",
+		keyStyle.Render("s:"), keyStyle.Render("t:"), keyStyle.Render("R:"), keyStyle.Render("p:"), keyStyle.Render("K:"),
+		keyStyle.Render("e/x:"), keyStyle.Render("o:"), keyStyle.Render("d:"))
+	helpText += fmt.Sprintf("  Info:       %s Inspect  %s Logs (merged for selection)  %s docker run command  %s Diff  %s Processes\n",
+		keyStyle.Render("i:"), keyStyle.Render("l:"), keyStyle.Render("g:"), keyStyle.Render("D:"), keyStyle.Render("T:"))
+	helpText += fmt.Sprintf("  Filters:    %s K8s  %s Exited  %s Paused  %s Group by project  %s Collapse/expand\n",
+		keyStyle.Render("h:"), keyStyle.Render("a:"), keyStyle.Render("z:"), keyStyle.Render("G:"), keyStyle.Render("enter:"))
+	helpText += fmt.Sprintf("  Stats:      %s Graphs  %s Columns  %s Sort (%s)\n",
+		keyStyle.Render("m:"), keyStyle.Render("M:"), keyStyle.Render("S:"), m.statsSort)
+	helpText += fmt.Sprintf("  Resources:  %s Images  %s Pull  %s Volumes  %s Networks  %s Disk usage\n",
+		keyStyle.Render("I:"), keyStyle.Render("P:"), keyStyle.Render("V:"), keyStyle.Render("N:"), keyStyle.Render("U:"))
+	helpText += fmt.Sprintf("  Other:      %s Run container  %s Export compose  %s Copy files  %s Browse files  %s Refresh  %s Quit",
+		keyStyle.Render("n:"), keyStyle.Render("E:"), keyStyle.Render("c:"), keyStyle.Render("b:"), keyStyle.Render("r:"), keyStyle.Render("q:"))
+
+	s.WriteString(helpStyle.Render(helpText))
+
+	return s.String()
+}
+
+// projectHeader returns the header line for a compose project
+func (m Model) projectHeader(project string) string {
+	containers := m.projectContainers(project)
+	running, paused := 0, 0
+	for _, c := range containers {
+		switch c.State {
+		case "running":
+			running++
+		case "paused":
+			paused++
+		}
+	}
+	arrow := "▾"
+	if m.collapsed[project] {
+		arrow = "▸"
+	}
+	if paused > 0 {
+		return fmt.Sprintf("%s %s (%d/%d running, %d paused)", arrow, project, running, len(containers), paused)
+	}
+	return fmt.Sprintf("%s %s (%d/%d running)", arrow, project, running, len(containers))
+}
+
+// Widths of the resource stats columns in the container list
+const (
+	statsCPUWidth = 7  // "100.00%"
+	statsMemWidth = 19 // "123.4MiB / 1.944GiB"
+	statsIOWidth  = 17 // "12.3MB / 456kB"
+)
+
+// statsHeader returns the resource column headers, marking the sort column
+func (m Model) statsHeader() string {
+	label := func(name string, key statsSortKey) string {
+		if m.statsSort == key {
+			return name + " ▼"
+		}
+		return name
+	}
+	return fmt.Sprintf("  %-*s  %-*s  %-*s  %-*s",
+		statsCPUWidth, label("CPU %", statsSortCPU),
+		statsMemWidth, label("MEM USAGE / LIMIT", statsSortMem),
+		statsIOWidth, label("NET I/O", statsSortNet),
+		statsIOWidth, label("BLOCK I/O", statsSortBlock))
+}
+
+// statsCells returns a container's resource columns ("-" until a sample arrives)
+func (m Model) statsCells(c containerInfo) string {
+	cpu, mem, network, block := "-", "-", "-", "-"
+	if sample, ok := m.stats[c.ID]; ok {
+		cpu = fmt.Sprintf("%.2f%%", sample.cpuPercent)
+		mem = formatBytes(sample.memUsage, true) + " / " + formatBytes(sample.memLimit, true)
+		network = formatBytes(sample.netRx, false) + " / " + formatBytes(sample.netTx, false)
+		block = formatBytes(sample.blockRead, false) + " / " + formatBytes(sample.blockWrite, false)
+	}
+	return fmt.Sprintf("  %-*s  %-*s  %-*s  %-*s",
+		statsCPUWidth, cpu, statsMemWidth, mem, statsIOWidth, network, statsIOWidth, block)
+}
+
+// viewImagesMode renders the images list
+func (m Model) viewImagesMode() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("🖼  Images") + "\n\n")
+
+	if m.imagesLoading && len(m.images) == 0 {
+		s.WriteString("Loading images...\n")
+	} else if len(m.images) == 0 {
+		s.WriteString("No images found.\n")
+	} else {
+		const (
+			idWidth       = 12
+			sizeWidth     = 8
+			createdWidth  = 22
+			danglingWidth = 8
+		)
+		refWidth := len("REPOSITORY:TAG")
+		for _, img := range m.images {
+			refWidth = max(refWidth, len(img.Ref))
+		}
+		// Give the remaining width to CONTAINERS, keeping at least 12 columns for it
+		fixed := 2 + idWidth + sizeWidth + createdWidth + danglingWidth + 5*2
+		refWidth = min(refWidth, max(m.width-fixed-12, 20))
+		containersWidth := max(m.width-fixed-refWidth, 10)
+
+		row := func(ref, id, size, created, dangling, containers string) string {
+			if len(ref) > refWidth {
+				ref = ref[:refWidth-3] + "..."
+			}
+			if len(containers) > containersWidth {
+				containers = containers[:containersWidth-3] + "..."
+			}
+			return fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s  %-*s",
+				refWidth, ref, idWidth, id, sizeWidth, size, createdWidth, created,
+				danglingWidth, dangling, containersWidth, containers)
+		}
+
+		s.WriteString(headerStyle.Render(" "+row("REPOSITORY:TAG", "IMAGE ID", "SIZE", "CREATED", "DANGLING", "CONTAINERS")) + "\n")
+		s.WriteString(dividerStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+
+		// Keep the cursor in view
+		available := max(m.height-14, 3)
+		start := max(0, min(m.imagesCursor-available/2, len(m.images)-available))
+		end := min(start+available, len(m.images))
+		for i := start; i < end; i++ {
+			img := m.images[i]
+			dangling := ""
+			if img.Dangling {
+				dangling = "yes"
+			}
+			containers := "-"
+			if len(img.Containers) > 0 {
+				containers = fmt.Sprintf("%d (%s)", len(img.Containers), strings.Join(img.Containers, ", "))
+			}
+			line := row(img.Ref, img.ID, formatBytes(float64(img.Size), false), formatAge(img.Created), dangling, containers)
+
+			switch {
+			case i == m.imagesCursor:
+				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+			case img.Dangling:
+				s.WriteString("  " + exitedStyle.Render(line) + "\n")
+			default:
+				s.WriteString("  " + line + "\n")
 			}
 		}
-	case containersLoadedMsg:
-		m.loading = false
-		if msg.err != nil {
-			m.err = msg.err
+		if len(m.images) > available {
+			s.WriteString(fmt.Sprintf("\nShowing %d-%d of %d images (scroll with ↑/↓)\n", start+1, end, len(m.images)))
 		} else {
-			m.allContainers = msg.containers
-			m.filterContainers()
-			if msg.showRefresh {
-				m.statusMsg = "Containers refreshed"
-				// Clear status after 2 seconds
-				return m, clearStatusAfterDelay(2 * time.Second)
-			} else if m.statusMsg == "" {
-				// Only set container count if no status message exists (initial load)
-				m.statusMsg = containerCountMsg(len(m.containers))
+			s.WriteString("\n")
+		}
+	}
+
+	if m.imageTagPrompt {
+		s.WriteString(statusStyle.Render("New tag (repo:tag): "+m.imageTagInput+"█") + "\n\n")
+	} else if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
+
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  %s Up  %s Down  %s Remove  %s Force remove  %s Tag  %s Inspect  %s Pull\n",
+		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("d:"), keyStyle.Render("D:"),
+		keyStyle.Render("t:"), keyStyle.Render("i:"), keyStyle.Render("p:"))
+	helpText += fmt.Sprintf("  %s Run container  %s Refresh  %s Back",
+		keyStyle.Render("n:"), keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
+
+// viewVolumesMode renders the volumes list
+func (m Model) viewVolumesMode() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("💾 Volumes") + "\n\n")
+
+	if m.volumesLoading && len(m.volumes) == 0 {
+		s.WriteString("Loading volumes...\n")
+	} else if len(m.volumes) == 0 {
+		s.WriteString("No volumes found.\n")
+	} else {
+		const (
+			driverWidth = 8
+			sizeWidth   = 8
+		)
+		nameWidth := len("VOLUME NAME")
+		for _, v := range m.volumes {
+			nameWidth = max(nameWidth, len(v.Name))
+		}
+		// Share the remaining width between the name, mountpoint and containers
+		remaining := max(m.width-2-driverWidth-sizeWidth-4*2, 30)
+		nameWidth = min(nameWidth, remaining*2/5)
+		mountWidth := remaining * 2 / 5
+		containersWidth := remaining - nameWidth - mountWidth
+
+		truncate := func(text string, width int) string {
+			if len(text) > width {
+				return text[:max(width-3, 0)] + "..."
 			}
-			// Otherwise preserve existing status message (for background refresh)
+			return text
 		}
-	case operationCompleteMsg:
-		m.statusMsg = msg.message
-		if msg.success {
-			// Refresh container list after successful operation (don't show refresh message)
-			m.loading = true
-			return m, m.loadContainers(false)
+		row := func(name, driver, size, mountpoint, containers string) string {
+			return fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s",
+				nameWidth, truncate(name, nameWidth), driverWidth, truncate(driver, driverWidth),
+				sizeWidth, size, mountWidth, truncate(mountpoint, mountWidth),
+				containersWidth, truncate(containers, containersWidth))
 		}
-	case clearStatusMsg:
-		// Clear status message and show standard status
-		m.statusMsg = containerCountMsg(len(m.containers))
-	case tickMsg:
-		// Auto-refresh containers in background (no loading spinner, no refresh message)
-		return m, tea.Batch(
-			m.loadContainers(false), // Silent refresh
-			tickCmd(),               // Schedule next tick
-		)
-	case inspectDataMsg:
-		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
-		} else {
-			m.inspectData = msg.data
-			m.currentView = viewInspect
-			m.statusMsg = ""
+
+		s.WriteString(headerStyle.Render(" "+row("VOLUME NAME", "DRIVER", "SIZE", "MOUNTPOINT", "CONTAINERS")) + "\n")
+		s.WriteString(dividerStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+
+		// Keep the cursor in view
+		available := max(m.height-14, 3)
+		start := max(0, min(m.volumesCursor-available/2, len(m.volumes)-available))
+		end := min(start+available, len(m.volumes))
+		for i := start; i < end; i++ {
+			v := m.volumes[i]
+			size := "..."
+			if v.Size >= 0 {
+				size = formatBytes(float64(v.Size), false)
+			}
+			containers := "-"
+			if len(v.Containers) > 0 {
+				containers = strings.Join(v.Containers, ", ")
+			}
+			line := row(v.Name, v.Driver, size, v.Mountpoint, containers)
+
+			switch {
+			case i == m.volumesCursor:
+				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+			case len(v.Containers) == 0:
+				// Unused volumes are muted, like exited containers
+				s.WriteString("  " + exitedStyle.Render(line) + "\n")
+			default:
+				s.WriteString("  " + line + "\n")
+			}
 		}
-	case logsDataMsg:
-		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		if len(m.volumes) > available {
+			s.WriteString(fmt.Sprintf("\nShowing %d-%d of %d volumes (scroll with ↑/↓)\n", start+1, end, len(m.volumes)))
 		} else {
-			m.logsData = msg.data
-			m.currentView = viewLogs
-			m.statusMsg = ""
+			s.WriteString("\n")
 		}
-	case shellReadyMsg:
-		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Failed to create shell: %v", msg.err)
-			m.currentView = viewList
+	}
+
+	if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
+
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  %s Up  %s Down  %s Remove  %s Prune unused\n",
+		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("d:"), keyStyle.Render("P:"))
+	helpText += fmt.Sprintf("  %s Refresh  %s Back", keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
+
+// viewRunMode renders the run form, the equivalent `docker run` command and
+// any validation problems
+func (m Model) viewRunMode() string {
+	var s strings.Builder
+	if m.runRecreate != nil {
+		s.WriteString(titleStyle.Render("♻️  Recreate Container: "+m.runRecreate.Name) + "\n")
+		s.WriteString(exitedStyle.Render("  Entrypoint, mounts, resource limits and other settings not shown here are kept.") + "\n\n")
+	} else {
+		s.WriteString(titleStyle.Render("🚀 Run Container") + "\n\n")
+	}
+
+	for i := runField(0); i < runFieldCount; i++ {
+		label := fmt.Sprintf("%-9s", runFields[i].label)
+		value := m.runForm[i]
+		if i == m.runFocus {
+			s.WriteString(selectedStyle.Render("▶ "+label) + " " + value + "█")
 		} else {
-			m.shellExecID = msg.execID
-			m.shellOutput = append(m.shellOutput, "Shell ready! Type commands below.", "")
+			s.WriteString("  " + keyStyle.Render(label) + " " + value)
 		}
-	case shellCommandResultMsg:
-		// Display command output
-		if msg.err != nil {
-			m.shellOutput = append(m.shellOutput, fmt.Sprintf("Error: %v", msg.err))
-		} else if msg.output != "" {
-			// Split output into lines and append each
-			lines := strings.Split(strings.TrimRight(msg.output, "\n"), "\n")
-			m.shellOutput = append(m.shellOutput, lines...)
+		if value == "" {
+			s.WriteString("  " + exitedStyle.Render(runFields[i].hint))
 		}
-		// Add blank line for readability
-		m.shellOutput = append(m.shellOutput, "")
+		s.WriteString("\n")
 	}
-	return m, nil
-}
 
-// View renders the UI
-func (m Model) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n\nPress q to quit.\n", m.err)
+	spec, err := parseRunForm(m.runForm)
+	s.WriteString("\n" + keyStyle.Render("Equivalent command:") + "\n")
+	s.WriteString("  " + formatCommand(spec.dockerRunArgs()) + "\n")
+	problems := m.runPortConflicts(spec)
+	if err != nil && spec.Image != "" {
+		problems = append([]string{err.Error()}, problems...)
+	}
+	for _, problem := range problems {
+		s.WriteString(warningStatusStyle.Render("⚠️  "+problem) + "\n")
 	}
+	s.WriteString("\n")
 
-	switch m.currentView {
-	case viewInspect:
-		return m.viewInspectMode()
-	case viewLogs:
-		return m.viewLogsMode()
-	case viewShell:
-		return m.viewShellMode()
-	case viewSearch:
-		return m.viewSearchMode()
-	default:
-		return m.viewListMode()
+	if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
+
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  %s Next field  %s Previous field  %s Clear field\n",
+		keyStyle.Render("tab/↓:"), keyStyle.Render("shift+tab/↑:"), keyStyle.Render("ctrl+u:"))
+	submit := "Create and start"
+	if m.runRecreate != nil {
+		submit = "Recreate"
 	}
+	helpText += fmt.Sprintf("  %s %s  %s Pull image  %s Cancel",
+		keyStyle.Render("enter:"), submit, keyStyle.Render("ctrl+p:"), keyStyle.Render("ESC:"))
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
 }
 
-// viewListMode renders the container list view
-func (m Model) viewListMode() string {
+// viewRunCommandMode renders the reconstructed docker run command
+func (m Model) viewRunCommandMode() string {
 	var s strings.Builder
+	s.WriteString(titleStyle.Render("🐳 docker run: "+m.runCommandName) + "\n\n")
+	s.WriteString(exitedStyle.Render("Values inherited from the image (env, labels, entrypoint, command, user, workdir) are left out.") + "\n\n")
+	s.WriteString(formatCommandLines(m.runCommand.dockerRunGroups()) + "\n\n")
 
-	// Title with styled connection info
-	title := "🐳 Local Container Manager (lcm)"
-	if m.socketPath != "" {
-		// socketPath now contains the platform name directly
-		title += " [Connected to: " + m.socketPath + "]"
+	if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
 	}
-	s.WriteString(titleStyle.Render(title) + "\n\n")
 
-	if m.loading {
-		s.WriteString("Loading containers...\n")
-	} else if len(m.containers) == 0 {
-		s.WriteString("No containers found.\n")
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  %s Copy to clipboard (one line)  %s Back", keyStyle.Render("c/y:"), keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
+
+// viewFilesMode renders the in-container file browser, or a file preview
+func (m Model) viewFilesMode() string {
+	var s strings.Builder
+	page := m.filesPageSize()
+
+	if m.filesPreviewing {
+		s.WriteString(titleStyle.Render(fmt.Sprintf("📄 %s:%s", m.filesContainer.Name, m.filesPreviewPath)) + "\n\n")
+		if m.filesPreviewNote != "" {
+			s.WriteString(exitedStyle.Render(m.filesPreviewNote) + "\n")
+		}
+		end := min(m.filesPreviewOffset+page, len(m.filesPreviewLines))
+		for _, line := range m.filesPreviewLines[min(m.filesPreviewOffset, end):end] {
+			if m.width > 0 && len(line) > m.width {
+				line = line[:m.width]
+			}
+			s.WriteString(line + "\n")
+		}
+		if len(m.filesPreviewLines) > page {
+			s.WriteString(exitedStyle.Render(fmt.Sprintf("Lines %d-%d of %d", m.filesPreviewOffset+1, end, len(m.filesPreviewLines))) + "\n")
+		}
+		s.WriteString("\n")
 	} else {
-		// Calculate how many containers we can show
-		// Account for: title(2) + header(1) + divider(1) + scroll/blank(2) + status(2) + help box(8) = 16 lines overhead
-		availableHeight := m.height - 16
-		if availableHeight < 3 {
-			availableHeight = 3 // Minimum
+		title := fmt.Sprintf("📂 Files: %s:%s", m.filesContainer.Name, m.filesPath)
+		if m.filesContainer.State != "running" {
+			title += " (" + m.filesContainer.State + ")"
 		}
+		s.WriteString(titleStyle.Render(title) + "\n\n")
 
-		// Calculate scroll window
-		startIdx := 0
-		endIdx := len(m.containers)
-
-		if len(m.containers) > availableHeight {
-			// Need to scroll
-			// Keep cursor in the middle when possible
-			half := availableHeight / 2
-			startIdx = m.cursor - half
-			if startIdx < 0 {
-				startIdx = 0
+		if m.filesLoading && len(m.filesEntries) == 0 {
+			s.WriteString("Loading...\n")
+		} else if len(m.filesEntries) == 0 {
+			s.WriteString("Empty directory.\n")
+		} else {
+			row := func(mode, size, modified, name string) string {
+				return fmt.Sprintf("%-10s  %9s  %-16s  %s", mode, size, modified, name)
 			}
-			endIdx = startIdx + availableHeight
-			if endIdx > len(m.containers) {
-				endIdx = len(m.containers)
-				startIdx = endIdx - availableHeight
-				if startIdx < 0 {
-					startIdx = 0
+			header := row("MODE", "SIZE", "MODIFIED", "NAME")
+			s.WriteString(headerStyle.Render(fmt.Sprintf(" %-*s", max(m.width-3, 0), header)) + "\n")
+			s.WriteString(dividerStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+
+			start := max(0, min(m.filesCursor-page/2, len(m.filesEntries)-page))
+			end := min(start+page, len(m.filesEntries))
+			for i := start; i < end; i++ {
+				e := m.filesEntries[i]
+				size, name := formatBytes(float64(e.Size), false), e.Name
+				switch {
+				case e.Mode.IsDir():
+					size, name = "-", name+"/"
+				case e.Mode&os.ModeSymlink != 0 && e.LinkTarget != "":
+					name += " -> " + e.LinkTarget
+				}
+				modified := "-"
+				if !e.Mtime.IsZero() {
+					modified = e.Mtime.Local().Format("2006-01-02 15:04")
+				}
+				line := row(e.Mode.String(), size, modified, name)
+				switch {
+				case i == m.filesCursor:
+					s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+				case e.Mode.IsDir():
+					s.WriteString("  " + keyStyle.Render(line) + "\n")
+				default:
+					s.WriteString("  " + line + "\n")
 				}
 			}
+			if len(m.filesEntries) > page {
+				s.WriteString(fmt.Sprintf("Showing %d-%d of %d entries (scroll with ↑/↓)\n", start+1, end, len(m.filesEntries)))
+			}
 		}
+		s.WriteString("\n")
+	}
 
-		// Responsive column layout with STATUS pinned to the right
-		// Fixed widths: ID=12, STATE=8 (covers "running"/"exited"/"created")
-		// Variable widths: NAME, IMAGE, OPENPORTS share remaining space
-		// STATUS: pinned to right edge
+	if m.copyStage != copyStageNone {
+		s.WriteString(statusStyle.Render(m.copyPromptText()+m.copyInput+"█") + "\n\n")
+	} else if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
 
-		const (
-			idWidth    = 12
-			stateWidth = 8
-			colSpacing = 2  // spaces between columns
-			cursorCol  = 2  // space for cursor indicator
-		)
+	helpText := "Controls:\n"
+	if m.filesPreviewing {
+		helpText += fmt.Sprintf("  %s Scroll  %s Page  %s Download  %s Back",
+			keyStyle.Render("↑/↓:"), keyStyle.Render("PgUp/PgDn:"), keyStyle.Render("d:"), keyStyle.Render("ESC/q:"))
+	} else {
+		helpText += fmt.Sprintf("  %s Up  %s Down  %s Open  %s Parent directory\n",
+			keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("enter/→:"), keyStyle.Render("backspace/←:"))
+		helpText += fmt.Sprintf("  %s Download  %s Upload here  %s Refresh  %s Back",
+			keyStyle.Render("d:"), keyStyle.Render("u:"), keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
+	}
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
 
-		// Calculate max content widths for variable columns
-		maxNameLen := len("NAME")
-		maxImageLen := len("IMAGE")
-		maxPortsLen := len("OPENPORTS")
-		maxStatusLen := len("STATUS")
+// viewDiffMode renders the container's filesystem changes as a tree
+func (m Model) viewDiffMode() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(fmt.Sprintf("🗂  Filesystem Changes: %s (%s)", m.diffContainer.Name, m.diffContainer.ID)) + "\n\n")
 
-		for i := startIdx; i < endIdx; i++ {
-			c := m.containers[i]
-			if len(c.Name) > maxNameLen {
-				maxNameLen = len(c.Name)
+	counts := func(c [3]int) string {
+		return fmt.Sprintf("%s %d  %s %d  %s %d",
+			diffKindStyles[container.ChangeAdd].Render("A"), c[container.ChangeAdd],
+			diffKindStyles[container.ChangeModify].Render("C"), c[container.ChangeModify],
+			diffKindStyles[container.ChangeDelete].Render("D"), c[container.ChangeDelete])
+	}
+
+	rows := m.diffRows()
+	if m.diffLoading && m.diffRoot == nil {
+		s.WriteString("Loading changes...\n")
+	} else if m.diffRoot == nil || len(rows) == 0 {
+		s.WriteString("No changes compared to the image.\n")
+	} else {
+		c := m.diffRoot.Counts
+		s.WriteString(fmt.Sprintf("Added %d  Changed %d  Deleted %d\n\n",
+			c[container.ChangeAdd], c[container.ChangeModify], c[container.ChangeDelete]))
+
+		available := max(m.height-14, 3)
+		start := max(0, min(m.diffCursor-available/2, len(rows)-available))
+		end := min(start+available, len(rows))
+		for i := start; i < end; i++ {
+			n := rows[i].node
+			marker := "  "
+			if len(n.Children) > 0 {
+				marker = "▾ "
+				if m.diffCollapsed[n.Path] {
+					marker = "▸ "
+				}
 			}
-			if len(c.Image) > maxImageLen {
-				maxImageLen = len(c.Image)
+			kind := " "
+			if n.Changed {
+				kind = n.Kind.String()
 			}
-			portsStr := strings.Join(c.Ports, ", ")
-			if portsStr == "" {
-				portsStr = "-"
+			name := n.Name
+			if len(n.Children) > 0 {
+				name += "/"
 			}
-			if len(portsStr) > maxPortsLen {
-				maxPortsLen = len(portsStr)
+			indent := strings.Repeat("  ", rows[i].depth)
+
+			if i == m.diffCursor {
+				line := indent + marker + kind + " " + name
+				if len(n.Children) > 0 {
+					line += fmt.Sprintf("  (A %d  C %d  D %d)", n.Counts[container.ChangeAdd], n.Counts[container.ChangeModify], n.Counts[container.ChangeDelete])
+				}
+				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+				continue
 			}
-			if len(c.Status) > maxStatusLen {
-				maxStatusLen = len(c.Status)
+			line := indent + marker + diffKindStyles[n.Kind].Render(kind) + " " + name
+			if len(n.Children) > 0 {
+				line += "  " + exitedStyle.Render("(") + counts(n.Counts) + exitedStyle.Render(")")
 			}
+			s.WriteString("  " + line + "\n")
 		}
-
-		// Calculate available width for variable columns
-		// Layout: [cursor] ID  NAME  IMAGE  OPENPORTS  STATE  [gap]  STATUS
-		fixedWidth := cursorCol + idWidth + stateWidth + (6 * colSpacing) + maxStatusLen
-		availableForVariable := m.width - fixedWidth
-
-		// Minimum widths for variable columns
-		minNameWidth := 10
-		minImageWidth := 10
-		minPortsWidth := 9 // "OPENPORTS"
-
-		// Distribute available space among NAME, IMAGE, OPENPORTS
-		// Give proportional space based on content, with minimums
-		totalContentWidth := maxNameLen + maxImageLen + maxPortsLen
-		if totalContentWidth == 0 {
-			totalContentWidth = 1
+		if len(rows) > available {
+			s.WriteString(fmt.Sprintf("Showing %d-%d of %d paths (scroll with ↑/↓)\n", start+1, end, len(rows)))
 		}
+	}
+	s.WriteString("\n")
 
-		var nameWidth, imageWidth, portsWidth int
-
-		if availableForVariable >= totalContentWidth {
-			// Enough space for all content - distribute extra space proportionally
-			// Start with content widths
-			nameWidth = maxNameLen
-			imageWidth = maxImageLen
-			portsWidth = maxPortsLen
-
-			// Calculate extra space and distribute it
-			extraSpace := availableForVariable - totalContentWidth
-			if extraSpace > 0 {
-				// Distribute extra space: 50% to NAME, 35% to IMAGE, 15% to OPENPORTS
-				// NAME gets more since it's typically the most important identifier
-				nameWidth += (extraSpace * 50) / 100
-				imageWidth += (extraSpace * 35) / 100
-				portsWidth += (extraSpace * 15) / 100
+	if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
 
-				// Distribute any remaining space due to rounding
-				remaining := availableForVariable - (nameWidth + imageWidth + portsWidth)
-				if remaining > 0 {
-					nameWidth += remaining
-				}
-			}
-		} else if availableForVariable >= minNameWidth+minImageWidth+minPortsWidth {
-			// Constrained: distribute proportionally with minimums
-			nameWidth = max(minNameWidth, availableForVariable*maxNameLen/totalContentWidth)
-			imageWidth = max(minImageWidth, availableForVariable*maxImageLen/totalContentWidth)
-			portsWidth = max(minPortsWidth, availableForVariable*maxPortsLen/totalContentWidth)
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  %s Up  %s Down  %s Toggle  %s Expand  %s Collapse/parent\n",
+		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("enter/space:"), keyStyle.Render("→/l:"), keyStyle.Render("←/h:"))
+	helpText += fmt.Sprintf("  %s Expand all  %s Collapse all  %s Refresh  %s Back",
+		keyStyle.Render("e:"), keyStyle.Render("c:"), keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
 
-			// Ensure we don't exceed available space
-			for nameWidth+imageWidth+portsWidth > availableForVariable {
-				if imageWidth > minImageWidth {
-					imageWidth--
-				} else if nameWidth > minNameWidth {
-					nameWidth--
-				} else if portsWidth > minPortsWidth {
-					portsWidth--
+// viewTopMode renders the processes running in a container
+func (m Model) viewTopMode() string {
+	var s strings.Builder
+	title := fmt.Sprintf("⚙️  Processes: %s (%s)", m.topContainer.Name, m.topContainer.ID)
+	s.WriteString(titleStyle.Render(title) + "\n")
+	s.WriteString(exitedStyle.Render(fmt.Sprintf("ps %s · sorted by %s · refreshed every %s", m.topArgs, topSortNames[m.topSort], topRefreshInterval)) + "\n\n")
+
+	if m.topLoading && len(m.topTitles) == 0 {
+		s.WriteString("Loading processes...\n")
+	} else if len(m.topTitles) == 0 {
+		s.WriteString("No processes (is the container running?)\n")
+	} else {
+		// Every column but the last (usually the command) is sized to fit
+		widths := make([]int, len(m.topTitles))
+		for i, t := range m.topTitles {
+			widths[i] = len(t)
+		}
+		for _, p := range m.topProcesses {
+			for i := 0; i < len(p) && i < len(widths); i++ {
+				widths[i] = max(widths[i], len(p[i]))
+			}
+		}
+		row := func(fields []string) string {
+			var cols []string
+			for i, f := range fields {
+				if i == len(fields)-1 {
+					cols = append(cols, f)
 				} else {
-					break
+					cols = append(cols, fmt.Sprintf("%-*s", widths[min(i, len(widths)-1)], f))
 				}
 			}
-		} else {
-			// Very narrow terminal: use minimums
-			nameWidth = minNameWidth
-			imageWidth = minImageWidth
-			portsWidth = minPortsWidth
+			line := strings.Join(cols, "  ")
+			if m.width > 4 && len(line) > m.width-3 {
+				line = line[:m.width-3]
+			}
+			return line
 		}
 
-		// Header - build left part, then pin STATE and STATUS to right
-		leftHeader := fmt.Sprintf(" %-*s  %-*s  %-*s  %-*s",
-			idWidth, "ID", nameWidth, "NAME", imageWidth, "IMAGE", portsWidth, "OPENPORTS")
+		s.WriteString(headerStyle.Render(fmt.Sprintf(" %-*s", max(m.width-3, 0), row(m.topTitles))) + "\n")
+		s.WriteString(dividerStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
 
-		// STATUS column width (add padding for readability)
-		statusWidth := maxStatusLen + 2
-		if statusWidth < 8 {
-			statusWidth = 8
+		available := max(m.height-14, 3)
+		start := max(0, min(m.topCursor-available/2, len(m.topProcesses)-available))
+		end := min(start+available, len(m.topProcesses))
+		for i := start; i < end; i++ {
+			line := row(m.topProcesses[i])
+			if i == m.topCursor {
+				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+			} else {
+				s.WriteString("  " + line + "\n")
+			}
 		}
+		if len(m.topProcesses) > available {
+			s.WriteString(fmt.Sprintf("Showing %d-%d of %d processes (scroll with ↑/↓)\n", start+1, end, len(m.topProcesses)))
+		}
+	}
+	s.WriteString("\n")
+
+	if m.topArgsPrompt {
+		s.WriteString(statusStyle.Render("ps arguments: "+m.topArgsInput+"█") + "\n\n")
+	} else if m.topSignalPicker {
+		s.WriteString(statusStyle.Render(fmt.Sprintf("Signal for PID %s: ", m.topSignalPID)) +
+			signalChoices(m.topSignal) + exitedStyle.Render("  (←/→ choose, enter send, esc cancel)") + "\n\n")
+	} else if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
 
-		// Right section: STATE + STATUS pinned to right edge
-		rightWidth := stateWidth + 2 + statusWidth // STATE + spacing + STATUS
+	helpText := "Controls:\n"
+	helpText += fmt.Sprintf("  %s Up  %s Down  %s Sort (PID/CPU/memory)  %s Send signal\n",
+		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("s:"), keyStyle.Render("K:"))
+	helpText += fmt.Sprintf("  %s ps arguments  %s Refresh  %s Back",
+		keyStyle.Render("a:"), keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
 
-		// Calculate gap to pin right section to right edge
-		headerGap := m.width - len(leftHeader) - rightWidth - cursorCol
-		if headerGap < 2 {
-			headerGap = 2
+// viewPullMode renders per-layer progress bars for the running pull
+func (m Model) viewPullMode() string {
+	var s strings.Builder
+	title := "📥 Pull Image"
+	if m.pullRef != "" {
+		title += ": " + m.pullRef
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	if m.pullPrompt {
+		s.WriteString(statusStyle.Render("Image to pull: "+m.pullInput+"█") + "\n\n")
+	}
+
+	if m.pullRef != "" {
+		for _, line := range m.pullLog {
+			s.WriteString(line + "\n")
 		}
 
-		// Build header with STATE and STATUS pinned right, both left-justified in their columns
-		rightHeader := fmt.Sprintf("%-*s  %-*s", stateWidth, "STATE", statusWidth, "STATUS")
-		// Ensure headerGap is non-negative
-		if headerGap < 0 {
-			headerGap = 0
+		const barWidth = 30
+		done := 0
+		var current, total int64
+		for _, l := range m.pullLayers {
+			if l.done() {
+				done++
+			}
+			if l.Status == "Downloading" {
+				current += l.Current
+				total += l.Total
+			}
 		}
-		headerText := leftHeader + strings.Repeat(" ", headerGap) + rightHeader
-		// Ensure header fills full width
-		if len(headerText) < m.width {
-			padding := m.width - len(headerText)
-			if padding > 0 {
-				headerText += strings.Repeat(" ", padding)
+		if len(m.pullLayers) > 0 {
+			s.WriteString("\n")
+		}
+
+		// Show the layers still in progress first when they don't all fit
+		available := max(m.height-16, 3)
+		layers := m.pullLayers
+		if len(layers) > available {
+			layers = nil
+			for _, l := range m.pullLayers {
+				if !l.done() {
+					layers = append(layers, l)
+				}
 			}
+			layers = layers[:min(len(layers), available)]
+		}
+		for _, l := range layers {
+			line := fmt.Sprintf("%-12s  %-18s", l.ID, l.Status)
+			if l.Total > 0 {
+				line += fmt.Sprintf("  %s  %s/%s", progressBar(l.Current, l.Total, barWidth),
+					formatBytes(float64(l.Current), false), formatBytes(float64(l.Total), false))
+			}
+			if l.done() {
+				line = exitedStyle.Render(line)
+			}
+			s.WriteString("  " + line + "\n")
 		}
-		s.WriteString(headerStyle.Render(headerText) + "\n")
 
-		// Full width divider
-		dividerWidth := m.width
-		if dividerWidth < 1 {
-			dividerWidth = 1
+		if len(m.pullLayers) > 0 {
+			summary := fmt.Sprintf("%d/%d layers complete", done, len(m.pullLayers))
+			if total > 0 {
+				summary += fmt.Sprintf(", downloading %s/%s", formatBytes(float64(current), false), formatBytes(float64(total), false))
+			}
+			s.WriteString("\n" + keyStyle.Render(summary) + "\n")
 		}
-		s.WriteString(dividerStyle.Render(strings.Repeat("─", dividerWidth)) + "\n")
+		s.WriteString("\n")
+	}
 
-		// Container list (scrollable window)
-		for i := startIdx; i < endIdx; i++ {
-			c := m.containers[i]
+	if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
 
-			// Truncate long names and images based on calculated widths
-			name := c.Name
-			if len(name) > nameWidth {
-				name = name[:nameWidth-3] + "..."
+	helpText := "Controls:\n"
+	switch {
+	case m.pullPrompt:
+		helpText += fmt.Sprintf("  %s Pull  %s Cancel", keyStyle.Render("enter:"), keyStyle.Render("ESC:"))
+	case m.pullRunning:
+		helpText += fmt.Sprintf("  %s Cancel pull", keyStyle.Render("ESC/q:"))
+	default:
+		helpText += fmt.Sprintf("  %s Pull another  %s Back", keyStyle.Render("p:"), keyStyle.Render("ESC/q:"))
+	}
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
+
+// viewDiskUsageMode renders space used and reclaimable per category, and
+// previews what the selected category's prune would delete
+func (m Model) viewDiskUsageMode() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("🧹 Disk Usage") + "\n\n")
+
+	if m.diskLoading && len(m.diskUsage) == 0 {
+		s.WriteString("Calculating disk usage...\n\n")
+	} else if len(m.diskUsage) > 0 {
+		row := func(typ, total, active, size, reclaimable, prune string) string {
+			return fmt.Sprintf("%-14s  %6s  %6s  %10s  %12s  %s", typ, total, active, size, reclaimable, prune)
+		}
+		header := row("TYPE", "TOTAL", "ACTIVE", "SIZE", "RECLAIMABLE", "PRUNE")
+		s.WriteString(headerStyle.Render(fmt.Sprintf(" %-*s", max(m.width-3, 0), header)) + "\n")
+		s.WriteString(dividerStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+
+		for i, r := range m.diskUsage {
+			reclaimable := formatBytes(float64(r.Reclaimable), false)
+			if r.Size > 0 {
+				reclaimable += fmt.Sprintf(" (%d%%)", r.Reclaimable*100/r.Size)
 			}
-			image := c.Image
-			if len(image) > imageWidth {
-				image = image[:imageWidth-3] + "..."
+			prune := fmt.Sprintf("%d %s", len(r.Candidates), r.Prune)
+			line := row(r.Type, strconv.Itoa(r.Total), strconv.Itoa(r.Active),
+				formatBytes(float64(r.Size), false), reclaimable, prune)
+			if i == m.diskCursor {
+				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+			} else {
+				s.WriteString("  " + line + "\n")
 			}
+		}
 
-			// Truncate ports if needed
-			portsStr := strings.Join(c.Ports, ", ")
-			if portsStr == "" {
-				portsStr = "-"
+		// Preview exactly what the selected prune would delete
+		if m.diskCursor < len(m.diskUsage) {
+			r := m.diskUsage[m.diskCursor]
+			s.WriteString("\n" + keyStyle.Render(fmt.Sprintf("Prune preview: %d %s, %s",
+				len(r.Candidates), r.Prune, formatBytes(float64(r.candidatesSize()), false))) + "\n")
+			if len(r.Candidates) == 0 {
+				s.WriteString("  (nothing to delete)\n")
 			}
-			if len(portsStr) > portsWidth {
-				portsStr = portsStr[:portsWidth-3] + "..."
+			available := m.diskPreviewHeight()
+			start := 0
+			if m.confirmingDiskPrune {
+				start = min(m.diskPreviewScroll, max(len(r.Candidates)-available, 0))
 			}
-
-			// Style state based on container status
-			stateText := c.State
-			stateRaw := c.State // Keep unstyled version for width calculation
-			if c.State == "running" {
-				stateText = runningStyle.Render(c.State)
-			} else if c.State == "exited" {
-				stateText = exitedStyle.Render(c.State)
+			end := min(start+available, len(r.Candidates))
+			for _, c := range r.Candidates[start:end] {
+				s.WriteString(fmt.Sprintf("  %-10s %s\n", formatBytes(float64(c.Size), false), c.Name))
+			}
+			if len(r.Candidates) > available {
+				hint := "press p to review them all"
+				if m.confirmingDiskPrune {
+					hint = "scroll with ↑/↓"
+				}
+				s.WriteString(fmt.Sprintf("  Showing %d-%d of %d %s (%s)\n", start+1, end, len(r.Candidates), r.Prune, hint))
 			}
+		}
+		s.WriteString("\n")
+	}
 
-			// Build left part of line (without cursor)
-			leftPart := fmt.Sprintf(" %-*s  %-*s  %-*s  %-*s",
-				idWidth, c.ID, nameWidth, name, imageWidth, image, portsWidth, portsStr)
+	if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
+	}
 
-			// Calculate gap to pin STATE and STATUS to right
-			lineGap := m.width - len(leftPart) - rightWidth - cursorCol
-			if lineGap < 2 {
-				lineGap = 2
-			}
+	helpText := "Controls:\n"
+	if m.confirmingDiskPrune {
+		helpText += fmt.Sprintf("  %s Scroll preview  %s Page\n",
+			keyStyle.Render("↑/↓:"), keyStyle.Render("PgUp/PgDn:"))
+		helpText += fmt.Sprintf("  %s Prune  %s Cancel", keyStyle.Render("y:"), keyStyle.Render("n/ESC:"))
+	} else {
+		helpText += fmt.Sprintf("  %s Up  %s Down  %s Prune selected (asks first)\n",
+			keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("p/enter:"))
+		helpText += fmt.Sprintf("  %s Refresh  %s Back", keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
+	}
+	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
 
-			// Build right section with styled STATE and STATUS, both left-justified
-			paddingCount := stateWidth - len(stateRaw)
-			if paddingCount < 0 {
-				paddingCount = 0
-			}
-			statePadding := strings.Repeat(" ", paddingCount)
-			rightPart := stateText + statePadding + "  " + fmt.Sprintf("%-*s", statusWidth, c.Status)
+// viewNetworksMode renders the networks list and the containers attached
+// to the selected network
+func (m Model) viewNetworksMode() string {
+	var s strings.Builder
+	title := "🌐 Networks"
+	if m.networkContainer.ID != "" {
+		title += " [container: " + m.networkContainer.Name + "]"
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
 
-			// Ensure lineGap is non-negative
-			if lineGap < 0 {
-				lineGap = 0
+	if m.networksLoading && len(m.networks) == 0 {
+		s.WriteString("Loading networks...\n")
+	} else if len(m.networks) == 0 {
+		s.WriteString("No networks found.\n")
+	} else {
+		const (
+			idWidth     = 12
+			driverWidth = 8
+			scopeWidth  = 6
+		)
+		nameWidth := len("NAME")
+		subnetWidth := len("SUBNET")
+		for _, n := range m.networks {
+			nameWidth = max(nameWidth, len(n.Name))
+			subnetWidth = max(subnetWidth, len(strings.Join(n.Subnets, ", ")))
+		}
+		nameWidth = min(nameWidth, 30)
+		subnetWidth = min(subnetWidth, 36)
+
+		row := func(name, id, driver, scope, subnet, gateway string) string {
+			if len(name) > nameWidth {
+				name = name[:nameWidth-3] + "..."
 			}
-			line := leftPart + strings.Repeat(" ", lineGap) + rightPart
+			if len(subnet) > subnetWidth {
+				subnet = subnet[:subnetWidth-3] + "..."
+			}
+			return fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s  %s",
+				nameWidth, name, idWidth, id, driverWidth, driver, scopeWidth, scope, subnetWidth, subnet, gateway)
+		}
 
-			if i == m.cursor {
-				// Highlight selected line - full width
+		header := row("NAME", "NETWORK ID", "DRIVER", "SCOPE", "SUBNET", "GATEWAY")
+		s.WriteString(headerStyle.Render(fmt.Sprintf(" %-*s", max(m.width-3, 0), header)) + "\n")
+		s.WriteString(dividerStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+
+		// Keep the cursor in view, leaving room for the attachments panel
+		available := max(m.height-22, 3)
+		start := max(0, min(m.networksCursor-available/2, len(m.networks)-available))
+		end := min(start+available, len(m.networks))
+		for i := start; i < end; i++ {
+			n := m.networks[i]
+			line := row(n.Name, n.ID, n.Driver, n.Scope, strings.Join(n.Subnets, ", "), strings.Join(n.Gateways, ", "))
+			switch {
+			case i == m.networksCursor:
 				s.WriteString(selectedStyle.Render("▶ "+line) + "\n")
-			} else {
+			case predefinedNetworks[n.Name]:
+				s.WriteString("  " + exitedStyle.Render(line) + "\n")
+			default:
 				s.WriteString("  " + line + "\n")
 			}
 		}
+		if len(m.networks) > available {
+			s.WriteString(fmt.Sprintf("Showing %d-%d of %d networks (scroll with ↑/↓)\n", start+1, end, len(m.networks)))
+		}
 
-		// Show scroll indicator if needed
-		if len(m.containers) > availableHeight {
-			s.WriteString(fmt.Sprintf("\nShowing %d-%d of %d containers (scroll with ↑/↓)\n",
-				startIdx+1, endIdx, len(m.containers)))
-		} else {
-			s.WriteString("\n")
+		// Attached containers for the selected network
+		if n, ok := m.selectedNetwork(); ok {
+			attached := m.networkAttachments(n.Name)
+			s.WriteString("\n" + keyStyle.Render(fmt.Sprintf("Containers on %s (%d):", n.Name, len(attached))) + "\n")
+			if len(attached) == 0 {
+				s.WriteString("  (none)\n")
+			}
+			for i, a := range attached {
+				if i == 5 {
+					s.WriteString(fmt.Sprintf("  ... and %d more\n", len(attached)-5))
+					break
+				}
+				s.WriteString("  " + a + "\n")
+			}
 		}
+		s.WriteString("\n")
 	}
 
-	// Status message - styled
-	if m.statusMsg != "" {
+	if m.networkCreatePrompt {
+		s.WriteString(statusStyle.Render("New bridge network name: "+m.networkCreateInput+"█") + "\n\n")
+	} else if m.statusMsg != "" {
 		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n\n")
 	}
 
-	// Help text - styled box with highlighted keys
 	helpText := "Controls:\n"
-	helpText += fmt.Sprintf("  Navigation: %s Up  %s Down  %s Search\n",
-		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("/:"))
-	helpText += fmt.Sprintf("  Actions:    %s Start  %s Stop  %s Restart  %s Shell  %s Browser  %s Destroy\n",
-		keyStyle.Render("s:"), keyStyle.Render("t:"), keyStyle.Render("R:"), keyStyle.Render("e/x:"), keyStyle.Render("o:"), keyStyle.Render("d:"))
-	helpText += fmt.Sprintf("  Info:       %s Inspect  %s Logs\n",
-		keyStyle.Render("i:"), keyStyle.Render("l:"))
-	helpText += fmt.Sprintf("  Filters:    %s K8s  %s Exited\n",
-		keyStyle.Render("h:"), keyStyle.Render("a:"))
-	helpText += fmt.Sprintf("  Other:      %s Refresh  %s Quit",
-		keyStyle.Render("r:"), keyStyle.Render("q:"))
-
+	helpText += fmt.Sprintf("  %s Up  %s Down  %s Connect container  %s Disconnect container\n",
+		keyStyle.Render("↑/k:"), keyStyle.Render("↓/j:"), keyStyle.Render("c:"), keyStyle.Render("x:"))
+	helpText += fmt.Sprintf("  %s Create  %s Remove  %s Refresh  %s Back",
+		keyStyle.Render("n:"), keyStyle.Render("d:"), keyStyle.Render("r:"), keyStyle.Render("ESC/q:"))
 	s.WriteString(helpStyle.Render(helpText))
+	return s.String()
+}
+
+// viewStatsMode renders rolling resource graphs for one container
+func (m Model) viewStatsMode() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(fmt.Sprintf("📈 Container Stats: %s (%s)", m.statsViewName, m.statsViewID)) + "\n\n")
+
+	if len(m.statsHistory) == 0 {
+		if m.statsViewEnded {
+			s.WriteString("No stats available (is the container running?)\n")
+		} else {
+			s.WriteString("Waiting for stats...\n")
+		}
+	} else {
+		latest := m.statsHistory[len(m.statsHistory)-1]
+		width := max(m.width-2, 20)
+		value := func(f func(statsSample) float64) []float64 {
+			values := make([]float64, len(m.statsHistory))
+			for i, sample := range m.statsHistory {
+				values[i] = f(sample)
+			}
+			return values
+		}
+		rate := func(counter func(statsSample) float64) (string, []float64) {
+			rates := statsRates(m.statsHistory, counter)
+			if len(rates) == 0 {
+				return "-", rates
+			}
+			return formatBytes(rates[len(rates)-1], false) + "/s", rates
+		}
+		graph := func(label, current string, values []float64) {
+			s.WriteString(keyStyle.Render(fmt.Sprintf("%-12s", label)) + current + "\n")
+			s.WriteString(runningStyle.Render(sparkline(values, width)) + "\n")
+		}
 
+		graph("CPU", fmt.Sprintf("%.2f%%", latest.cpuPercent),
+			value(func(s statsSample) float64 { return s.cpuPercent }))
+		graph("Memory", fmt.Sprintf("%s / %s (%.2f%%)",
+			formatBytes(latest.memUsage, true), formatBytes(latest.memLimit, true), latest.memPercent),
+			value(func(s statsSample) float64 { return s.memUsage }))
+		current, rates := rate(func(s statsSample) float64 { return s.netRx })
+		graph("Net RX", current, rates)
+		current, rates = rate(func(s statsSample) float64 { return s.netTx })
+		graph("Net TX", current, rates)
+		current, rates = rate(func(s statsSample) float64 { return s.blockRead })
+		graph("Block read", current, rates)
+		current, rates = rate(func(s statsSample) float64 { return s.blockWrite })
+		graph("Block write", current, rates)
+
+		window := latest.time.Sub(m.statsHistory[0].time).Round(time.Second)
+		s.WriteString(fmt.Sprintf("\nPIDs: %d  |  Memory limit: %s  |  Window: %s (%d samples)\n",
+			latest.pids, formatBytes(latest.memLimit, true), window, len(m.statsHistory)))
+		if m.statsViewEnded {
+			s.WriteString(warningStatusStyle.Render("Stats stream ended") + "\n")
+		}
+	}
+
+	if m.statusMsg != "" {
+		s.WriteString("\n" + statusStyle.Render("● "+m.statusMsg) + "\n")
+	}
+
+	footerText := fmt.Sprintf("%s Back", keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(footerText))
 	return s.String()
 }
 
-// viewInspectMode renders the container inspection view
+// viewInspectMode renders the inspect view: a tab bar and the current tab
 func (m Model) viewInspectMode() string {
 	var s strings.Builder
-	s.WriteString(titleStyle.Render("🔍 Container Inspection") + "\n")
+	title := m.inspectTitle
+	if title == "" {
+		title = "🔍 Container Inspection"
+		if m.inspectInfo != nil && m.inspectInfo.ContainerJSONBase != nil {
+			title += ": " + strings.TrimPrefix(m.inspectInfo.Name, "/")
+		}
+	}
+	s.WriteString(titleStyle.Render(title) + "\n")
+
+	tabs := m.inspectTabNames()
+	if len(tabs) > 1 {
+		var bar []string
+		for i, tab := range tabs {
+			label := fmt.Sprintf("%d %s", i+1, tab)
+			if i == m.inspectTab {
+				bar = append(bar, selectedStyle.Render(" "+label+" "))
+			} else {
+				bar = append(bar, exitedStyle.Render(" "+label+" "))
+			}
+		}
+		s.WriteString(strings.Join(bar, "") + "\n")
+	}
 	// Full width divider
 	dividerWidth := m.width
 	if dividerWidth < 40 {
 		dividerWidth = 40
 	}
-	s.WriteString(dividerStyle.Render(strings.Repeat("─", dividerWidth)) + "\n\n")
+	s.WriteString(dividerStyle.Render(strings.Repeat("─", dividerWidth)) + "\n")
 
-	if m.inspectData != "" {
-		// Truncate lines that are too long for the terminal
-		lines := strings.Split(m.inspectData, "\n")
-		maxLines := m.height - 6 // Leave room for header and footer
-		if maxLines < 10 {
-			maxLines = 10
-		}
+	raw := tabs[m.inspectTab] == "Raw JSON"
+	if raw && m.inspectQueryPrompt {
+		s.WriteString(statusStyle.Render("Query: "+m.inspectQueryInput+"█") + exitedStyle.Render("  e.g. .State.Health.Status, Mounts[*].Source") + "\n")
+	} else if raw && m.inspectQuery != "" {
+		s.WriteString(filterStyle.Render("Query: "+m.inspectQuery) + "\n")
+	}
+	s.WriteString("\n")
+
+	lines := m.inspectTabLines()
+	page := m.inspectPageSize()
+	start := min(m.inspectOffset, max(len(lines)-page, 0))
+	end := min(start+page, len(lines))
+	for _, line := range lines[start:end] {
+		s.WriteString(line + "\n")
+	}
+	if len(lines) > page {
+		s.WriteString(exitedStyle.Render(fmt.Sprintf("Lines %d-%d of %d", start+1, end, len(lines))) + "\n")
+	}
+	s.WriteString("\n")
 
-		displayLines := lines
-		if len(lines) > maxLines {
-			displayLines = lines[:maxLines]
-			s.WriteString(strings.Join(displayLines, "\n"))
-			s.WriteString(fmt.Sprintf("\n\n... (showing %d of %d lines, scroll down for more)", maxLines, len(lines)))
-		} else {
-			s.WriteString(strings.Join(displayLines, "\n"))
+	helpText := fmt.Sprintf("%s Scroll  %s Page", keyStyle.Render("↑/↓:"), keyStyle.Render("PgUp/PgDn:"))
+	if len(tabs) > 1 {
+		helpText += fmt.Sprintf("  %s Switch tab", keyStyle.Render("tab/←/→/1-8:"))
+	}
+	if raw {
+		helpText += fmt.Sprintf("  %s Query (empty shows all)", keyStyle.Render("/:"))
+	}
+	helpText += fmt.Sprintf("  %s Back", keyStyle.Render("ESC/q:"))
+	s.WriteString(helpStyle.Render(helpText) + "\n")
+	return s.String()
+}
+
+// highlightMatches renders text with textStyle and every match of re with matchStyle
+func highlightMatches(text string, re *regexp.Regexp, textStyle, matchStyle lipgloss.Style) string {
+	var matches [][]int
+	if re != nil {
+		matches = re.FindAllStringIndex(text, -1)
+	}
+	if len(matches) == 0 {
+		return textStyle.Render(text)
+	}
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		if match[0] == match[1] {
+			continue // Skip empty matches
 		}
-		s.WriteString("\n\n")
+		if match[0] > last {
+			b.WriteString(textStyle.Render(text[last:match[0]]))
+		}
+		b.WriteString(matchStyle.Render(text[match[0]:match[1]]))
+		last = match[1]
+	}
+	if last < len(text) {
+		b.WriteString(textStyle.Render(text[last:]))
 	}
+	return b.String()
+}
 
-	footerText := fmt.Sprintf("Press %s or %s to return to list",
-		keyStyle.Render("ESC"), keyStyle.Render("q"))
-	s.WriteString("\n" + helpStyle.Render(footerText) + "\n")
-	return s.String()
+// logsLinePrefix returns the coloured "name | " prefix for a line in the
+// merged view; wrapped continuation rows get matching blank padding
+func (m Model) logsLinePrefix(source int, first bool) string {
+	width := m.logsPrefixWidth()
+	if width == 0 {
+		return ""
+	}
+	if !first || source >= len(m.logsSources) {
+		return strings.Repeat(" ", width)
+	}
+	name := m.logsSources[source].name
+	if len(name) > width-3 {
+		name = name[:width-3]
+	}
+	style := lipgloss.NewStyle().Foreground(logSourceColors[source%len(logSourceColors)])
+	return style.Render(fmt.Sprintf("%-*s | ", width-3, name))
 }
 
-// viewLogsMode renders the container logs view
+// viewLogsMode renders the container logs pager
 func (m Model) viewLogsMode() string {
 	var s strings.Builder
-	s.WriteString(titleStyle.Render("📋 Container Logs (last 100 lines)") + "\n")
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	matchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#000000")).Background(warningColor)
+
+	var names []string
+	for _, source := range m.logsSources {
+		names = append(names, source.name)
+	}
+	title := "📋 Container Logs: " + strings.Join(names, ", ")
+	if m.logsMerged() {
+		title = "📋 Merged Logs: " + strings.Join(names, ", ")
+	}
+	switch {
+	case m.logsEnded:
+		title += " (stream ended)"
+	case m.logsPaused:
+		title += " (paused)"
+	case m.logsFollow:
+		title += " (following)"
+	}
+	s.WriteString(titleStyle.Render(title) + "\n")
 	// Full width divider
 	dividerWidth := m.width
 	if dividerWidth < 40 {
 		dividerWidth = 40
 	}
-	s.WriteString(dividerStyle.Render(strings.Repeat("─", dividerWidth)) + "\n\n")
-
-	if m.logsData != "" {
-		// Truncate lines that are too long for the terminal
-		lines := strings.Split(m.logsData, "\n")
-		maxLines := m.height - 6 // Leave room for header and footer
-		if maxLines < 10 {
-			maxLines = 10
+	s.WriteString(dividerStyle.Render(strings.Repeat("─", dividerWidth)) + "\n")
+
+	page := m.logsPageHeight()
+	rows := 0
+	visible := m.logsVisibleLines()
+	total := len(visible)
+	start, end := 0, 0
+	if total > 0 {
+		start = m.logsStart(visible)
+		width := m.logsLineWidth()
+		for end = start; end < total && rows < page; end++ {
+			line := m.logsBuffer.Line(visible[end])
+			textStyle := lipgloss.NewStyle()
+			if line.stderr {
+				textStyle = stderrStyle
+			}
+			for i, segment := range logSegments(line.text, width, m.logsWrap) {
+				if rows == page {
+					break
+				}
+				s.WriteString(m.logsLinePrefix(line.source, i == 0))
+				s.WriteString(highlightMatches(segment, m.logsSearch, textStyle, matchStyle) + "\n")
+				rows++
+			}
 		}
+	}
+	if total == 0 {
+		s.WriteString(mutedStyle.Render("Waiting for log output...") + "\n")
+		rows++
+	}
+	for ; rows < page; rows++ {
+		s.WriteString("\n")
+	}
 
-		displayLines := lines
-		if len(lines) > maxLines {
-			// Show the last N lines (most recent logs)
-			displayLines = lines[len(lines)-maxLines:]
-			mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
-			s.WriteString(mutedStyle.Render(fmt.Sprintf("... (showing last %d of %d lines)\n\n", maxLines, len(lines))))
-			s.WriteString(strings.Join(displayLines, "\n"))
-		} else {
-			s.WriteString(strings.Join(displayLines, "\n"))
+	// Position and settings line
+	position := fmt.Sprintf("Lines %d-%d of %d", min(start+1, end), end, total)
+	if m.logsBuffer != nil && m.logsBuffer.Dropped() > 0 {
+		position += fmt.Sprintf(" (%d older discarded)", m.logsBuffer.Dropped())
+	}
+	position += "  |  tail: " + m.logsTail
+	if m.logsSince != "" {
+		position += "  since: " + m.logsSince
+	}
+	position += "  |  streams: " + m.logsStreams.String()
+	if m.logsMerged() {
+		var toggles []string
+		for i, source := range m.logsSources {
+			state := "on"
+			if source.hidden {
+				state = "off"
+			}
+			toggles = append(toggles, fmt.Sprintf("%d:%s %s", i+1, source.name, state))
 		}
-		s.WriteString("\n\n")
+		position += "  |  " + strings.Join(toggles, " ")
+	}
+	if m.logsTimestamps || m.logsMerged() {
+		position += "  |  timestamps"
+	}
+	if m.logsWrap {
+		position += "  |  wrap"
+	}
+	if m.logsSearch != nil {
+		position += "  |  /" + m.logsSearch.String()
+	}
+	s.WriteString("\n" + mutedStyle.Render(position) + "\n")
+
+	// Prompt replaces the status line while typing
+	if m.logsPrompt != "" {
+		labels := map[string]string{"search": "/", "tail": "Tail lines (number or all): ", "since": "Since (10m, 2h, RFC3339; empty for none): "}
+		s.WriteString(keyStyle.Render(labels[m.logsPrompt]) + m.logsPromptInput + "█\n")
+	} else if m.statusMsg != "" {
+		s.WriteString(statusStyle.Render("● "+m.statusMsg) + "\n")
+	} else {
+		s.WriteString("\n")
 	}
 
-	footerText := fmt.Sprintf("Press %s or %s to return to list",
-		keyStyle.Render("ESC"), keyStyle.Render("q"))
-	s.WriteString("\n" + helpStyle.Render(footerText) + "\n")
+	pauseLabel := "Pause"
+	if m.logsPaused {
+		pauseLabel = "Resume"
+	}
+	footerText := fmt.Sprintf("%s Scroll  %s Page  %s Top/End  %s Search  %s Next/Prev  %s Wrap\n",
+		keyStyle.Render("↑/↓:"), keyStyle.Render("PgUp/PgDn:"), keyStyle.Render("g/G:"),
+		keyStyle.Render("/:"), keyStyle.Render("n/N:"), keyStyle.Render("w:"))
+	footerText += fmt.Sprintf("%s Tail  %s Since  %s Streams  %s Timestamps  %s %s  %s Back",
+		keyStyle.Render("t:"), keyStyle.Render("s:"), keyStyle.Render("o:"), keyStyle.Render("T:"),
+		keyStyle.Render("p:"), pauseLabel, keyStyle.Render("ESC/q:"))
+	if m.logsMerged() {
+		footerText += fmt.Sprintf("  %s Toggle source", keyStyle.Render("1-9:"))
+	}
+	s.WriteString(helpStyle.Render(footerText))
 	return s.String()
 }
 
-// viewShellMode renders the shell popup overlay
-func (m Model) viewShellMode() string {
-	// Calculate popup dimensions (80% of terminal size, min 60x20)
-	popupWidth := int(float64(m.width) * 0.8)
+// shellPopupSize returns the shell popup dimensions (80% of terminal size, min 60x20)
+func shellPopupSize(width, height int) (int, int) {
+	popupWidth := int(float64(width) * 0.8)
 	if popupWidth < 60 {
 		popupWidth = 60
 	}
-	if popupWidth > m.width-4 {
-		popupWidth = m.width - 4
+	if popupWidth > width-4 {
+		popupWidth = width - 4
 	}
 
-	popupHeight := int(float64(m.height) * 0.8)
+	popupHeight := int(float64(height) * 0.8)
 	if popupHeight < 20 {
 		popupHeight = 20
 	}
-	if popupHeight > m.height-4 {
-		popupHeight = m.height - 4
+	if popupHeight > height-4 {
+		popupHeight = height - 4
 	}
+	return popupWidth, popupHeight
+}
+
+// shellTermSize returns the TTY size (columns, rows) that fits inside the shell popup
+func shellTermSize(width, height int) (int, int) {
+	popupWidth, popupHeight := shellPopupSize(width, height)
+	// Horizontal padding is 2 on each side; vertically the padding plus
+	// title, divider, footer divider and help line take 6 rows
+	return max(popupWidth-4, 10), max(popupHeight-6, 5)
+}
+
+// viewShellMode renders the shell popup overlay
+func (m Model) viewShellMode() string {
+	popupWidth, popupHeight := shellPopupSize(m.width, m.height)
 
 	// Create shell content
 	var shellContent strings.Builder
 
 	// Title
 	shellTitle := fmt.Sprintf("🐚 Shell: %s", m.shellContainerName)
-	shellContent.WriteString(titleStyle.Render(shellTitle) + "\n")
-	shellContent.WriteString(dividerStyle.Render(strings.Repeat("─", popupWidth-4)) + "\n\n")
-
-	// Output area (scrollable)
-	outputHeight := popupHeight - 8 // Leave room for header, input, footer
-	if outputHeight < 5 {
-		outputHeight = 5
-	}
-
-	// Show recent output lines
-	startLine := 0
-	if len(m.shellOutput) > outputHeight {
-		startLine = len(m.shellOutput) - outputHeight
+	if m.shellConn == nil {
+		shellTitle += " (connecting...)"
 	}
+	shellContent.WriteString(titleStyle.Render(shellTitle) + "\n")
+	shellContent.WriteString(dividerStyle.Render(strings.Repeat("─", popupWidth-4)) + "\n")
 
-	for i := startLine; i < len(m.shellOutput); i++ {
-		line := m.shellOutput[i]
-		if len(line) > popupWidth-6 {
-			line = line[:popupWidth-9] + "..."
+	// Terminal screen, with the cursor drawn as a reversed cell
+	if m.shellScreen != nil {
+		screen := m.shellScreen
+		cursorStyle := lipgloss.NewStyle().Reverse(true)
+		for y, line := range screen.Lines() {
+			if y == screen.cursorY && !screen.cursorHidden && m.shellConn != nil {
+				row := []rune(line)
+				x := screen.cursorX
+				line = string(row[:x]) + cursorStyle.Render(string(row[x])) + string(row[x+1:])
+			}
+			shellContent.WriteString(line + "\n")
 		}
-		shellContent.WriteString(line + "\n")
-	}
-
-	// Fill remaining space
-	for i := len(m.shellOutput) - startLine; i < outputHeight; i++ {
-		shellContent.WriteString("\n")
 	}
 
-	// Input line
-	shellContent.WriteString("\n")
-	shellContent.WriteString(dividerStyle.Render(strings.Repeat("─", popupWidth-4)) + "\n")
-	inputPrompt := runningStyle.Render("$ ") + m.shellInput + "█"
-	shellContent.WriteString(inputPrompt + "\n")
-
 	// Help text
-	helpText := fmt.Sprintf("%s exit shell  |  %s send command",
-		keyStyle.Render("ESC"), keyStyle.Render("ENTER"))
+	shellContent.WriteString(dividerStyle.Render(strings.Repeat("─", popupWidth-4)) + "\n")
+	helpText := fmt.Sprintf("%s close shell  |  ESC and Ctrl+C go to the shell",
+		keyStyle.Render("Ctrl+]"))
 	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
 	shellContent.WriteString(mutedStyle.Render(helpText))
 
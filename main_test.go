@@ -1,13 +1,42 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 )
 
+// update passes msg to the model's Update, keeps the updated model and
+// returns the command
+func update(model *Model, msg tea.Msg) tea.Cmd {
+	updatedModel, cmd := model.Update(msg)
+	*model = updatedModel.(Model)
+	return cmd
+}
+
 // TestGetContainerPlatforms verifies that the platform list is correctly generated
 func TestGetContainerPlatforms(t *testing.T) {
 	platforms := getContainerPlatforms()
@@ -421,3 +450,2487 @@ func TestDestroyIgnoresOtherKeys(t *testing.T) {
 		t.Errorf("Expected containerToDestroy 'abc123', got %q", m.containerToDestroy)
 	}
 }
+
+// TestContainerEventDestroyRemovesContainer verifies destroy events are applied locally
+func TestContainerEventDestroyRemovesContainer(t *testing.T) {
+	model := Model{
+		currentView: viewList,
+		allContainers: []containerInfo{
+			{ID: "abc123456789", Name: "web", State: "exited"},
+			{ID: "def123456789", Name: "db", State: "running"},
+		},
+	}
+	model.filterContainers()
+
+	msg := containerEventMsg{event: events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDestroy,
+		Actor:  events.Actor{ID: "abc123456789ffffffffffff"},
+	}}
+
+	updatedModel, _ := model.Update(msg)
+	m := updatedModel.(Model)
+
+	if len(m.allContainers) != 1 || m.allContainers[0].ID != "def123456789" {
+		t.Errorf("Expected only db to remain, got %+v", m.allContainers)
+	}
+}
+
+// TestContainerEventRename verifies rename events update the name in place
+func TestContainerEventRename(t *testing.T) {
+	model := Model{
+		currentView: viewList,
+		allContainers: []containerInfo{
+			{ID: "abc123456789", Name: "old-name", State: "running"},
+		},
+	}
+	model.filterContainers()
+
+	msg := containerEventMsg{event: events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionRename,
+		Actor: events.Actor{
+			ID:         "abc123456789ffffffffffff",
+			Attributes: map[string]string{"name": "new-name"},
+		},
+	}}
+
+	updatedModel, _ := model.Update(msg)
+	m := updatedModel.(Model)
+
+	if m.containers[0].Name != "new-name" {
+		t.Errorf("Expected name 'new-name', got %q", m.containers[0].Name)
+	}
+}
+
+// TestContainerUpdatedUpsert verifies single-container refreshes replace or add entries
+func TestContainerUpdatedUpsert(t *testing.T) {
+	model := Model{
+		currentView: viewList,
+		allContainers: []containerInfo{
+			{ID: "abc123456789", Name: "web", State: "running"},
+		},
+	}
+	model.filterContainers()
+
+	// Existing container changes state
+	updated := containerInfo{ID: "abc123456789", Name: "web", State: "paused"}
+	updatedModel, _ := model.Update(containerUpdatedMsg{id: updated.ID, info: &updated})
+	m := updatedModel.(Model)
+	if len(m.allContainers) != 1 || m.allContainers[0].State != "paused" {
+		t.Errorf("Expected web to be paused, got %+v", m.allContainers)
+	}
+
+	// New container is added to the front
+	created := containerInfo{ID: "def123456789", Name: "db", State: "running"}
+	updatedModel, _ = m.Update(containerUpdatedMsg{id: created.ID, info: &created})
+	m = updatedModel.(Model)
+	if len(m.allContainers) != 2 || m.allContainers[0].ID != "def123456789" {
+		t.Errorf("Expected db to be added first, got %+v", m.allContainers)
+	}
+
+	// Missing container is removed
+	updatedModel, _ = m.Update(containerUpdatedMsg{id: "abc123456789"})
+	m = updatedModel.(Model)
+	if len(m.allContainers) != 1 || m.allContainers[0].ID != "def123456789" {
+		t.Errorf("Expected web to be removed, got %+v", m.allContainers)
+	}
+}
+
+// TestContainerListReconciledWithEvents verifies a full list requested
+// before some events keeps what those events changed
+func TestContainerListReconciledWithEvents(t *testing.T) {
+	model := Model{
+		currentView: viewList,
+		loading:     true,
+		allContainers: []containerInfo{
+			{ID: "aaa123456789", Name: "web", State: "running"},
+			{ID: "bbb123456789", Name: "db", State: "running"},
+		},
+	}
+	model.filterContainers()
+	requested := model.eventsSeq
+	event := func(action events.Action, id string) {
+		update(&model, containerEventMsg{event: events.Message{Type: events.ContainerEventType, Action: action, Actor: events.Actor{ID: id}}})
+	}
+
+	event(events.ActionDie, "aaa123456789")
+	update(&model, containerUpdatedMsg{id: "aaa123456789", seq: model.eventsSeq, info: &containerInfo{ID: "aaa123456789", Name: "web", State: "exited"}})
+	event(events.ActionDestroy, "bbb123456789")
+	event(events.ActionCreate, "ccc123456789")
+	update(&model, containerUpdatedMsg{id: "ccc123456789", seq: model.eventsSeq, info: &containerInfo{ID: "ccc123456789", Name: "new", State: "created"}})
+
+	update(&model, containersLoadedMsg{seq: requested, containers: []containerInfo{
+		{ID: "aaa123456789", Name: "web", State: "running"},
+		{ID: "bbb123456789", Name: "db", State: "running"},
+		{ID: "ddd123456789", Name: "other", State: "running"},
+	}})
+	var got []string
+	for _, c := range model.allContainers {
+		got = append(got, c.Name+":"+c.State)
+	}
+	if strings.Join(got, ",") != "new:created,web:exited,other:running" || model.loading {
+		t.Errorf("Expected the list merged with the newer events, got %v", got)
+	}
+
+	// A list requested after the events is applied as is
+	update(&model, containersLoadedMsg{seq: model.eventsSeq, containers: []containerInfo{{ID: "ddd123456789", Name: "other", State: "exited"}}})
+	if len(model.allContainers) != 1 || model.allContainers[0].State != "exited" || len(model.containerSeq) != 0 {
+		t.Errorf("Expected the newer list to replace everything, got %+v", model.allContainers)
+	}
+}
+
+// TestContainerUpdatesOutOfOrder verifies a fetch for an earlier event that
+// returns last doesn't overwrite the result of a later one
+func TestContainerUpdatesOutOfOrder(t *testing.T) {
+	model := Model{
+		currentView:   viewList,
+		allContainers: []containerInfo{{ID: "aaa123456789", Name: "web", State: "created"}},
+	}
+	model.filterContainers()
+	for _, action := range []events.Action{events.ActionStart, events.ActionDie} {
+		update(&model, containerEventMsg{event: events.Message{Type: events.ContainerEventType, Action: action, Actor: events.Actor{ID: "aaa123456789"}}})
+	}
+
+	update(&model, containerUpdatedMsg{id: "aaa123456789", seq: 2, info: &containerInfo{ID: "aaa123456789", Name: "web", State: "exited"}})
+	update(&model, containerUpdatedMsg{id: "aaa123456789", seq: 1, info: &containerInfo{ID: "aaa123456789", Name: "web", State: "running"}})
+	if model.allContainers[0].State != "exited" {
+		t.Errorf("Expected the die result to win, got %s", model.allContainers[0].State)
+	}
+}
+
+// TestEventsStreamDropFallsBack verifies a stream error switches to periodic resyncs
+func TestEventsStreamDropFallsBack(t *testing.T) {
+	model := Model{
+		currentView:  viewList,
+		eventsActive: true,
+	}
+
+	updatedModel, cmd := model.Update(eventsStreamErrMsg{err: io.EOF})
+	m := updatedModel.(Model)
+
+	if m.eventsActive {
+		t.Error("Expected eventsActive to be false after stream error")
+	}
+	if cmd == nil {
+		t.Error("Expected a resync tick to be scheduled")
+	}
+}
+
+// TestTerminalScreenText verifies printing, newlines and wrapping in the shell emulator
+func TestTerminalScreenText(t *testing.T) {
+	screen := newTerminalScreen(5, 3)
+	screen.Write([]byte("ab\r\ncdefgh"))
+
+	lines := screen.Lines()
+	expected := []string{"ab   ", "cdefg", "h    "}
+	for i, want := range expected {
+		if lines[i] != want {
+			t.Errorf("Line %d: expected %q, got %q", i, want, lines[i])
+		}
+	}
+
+	// Writing past the last row scrolls the screen up
+	screen.Write([]byte("\r\nxyz"))
+	lines = screen.Lines()
+	if lines[0] != "cdefg" || lines[2] != "xyz  " {
+		t.Errorf("Expected screen to scroll, got %q", lines)
+	}
+}
+
+// TestTerminalScreenControlSequences verifies cursor movement, erase and alternate screen
+func TestTerminalScreenControlSequences(t *testing.T) {
+	screen := newTerminalScreen(10, 3)
+	screen.Write([]byte("hello\x1b[2;3Hhi\x1b[1;1H\x1b[2K"))
+
+	lines := screen.Lines()
+	if lines[0] != "          " {
+		t.Errorf("Expected first line erased, got %q", lines[0])
+	}
+	if lines[1] != "  hi      " {
+		t.Errorf("Expected cursor-positioned text, got %q", lines[1])
+	}
+
+	// Alternate screen is blank and the main screen is restored on exit
+	screen.Write([]byte("\x1b[?1049h"))
+	if screen.Lines()[1] != "          " {
+		t.Errorf("Expected blank alternate screen, got %q", screen.Lines()[1])
+	}
+	screen.Write([]byte("\x1b[?1049l"))
+	if screen.Lines()[1] != "  hi      " {
+		t.Errorf("Expected main screen restored, got %q", screen.Lines()[1])
+	}
+
+	// Colours are ignored and split UTF-8 sequences are reassembled
+	screen.Write([]byte("\x1b[3;1H\x1b[31mé\xe2\x82"))
+	screen.Write([]byte("\xac\x1b[0m"))
+	if screen.Lines()[2] != "é€        " {
+		t.Errorf("Expected coloured UTF-8 text, got %q", screen.Lines()[2])
+	}
+}
+
+// TestTerminalScreenResizeClampsSavedCursor verifies a cursor saved before
+// the screen shrank is restored inside the new grid
+func TestTerminalScreenResizeClampsSavedCursor(t *testing.T) {
+	screen := newTerminalScreen(80, 40)
+	screen.Write([]byte("\x1b[40;80H\x1b[?1049h"))
+	screen.Resize(40, 10)
+	screen.Write([]byte("\x1b[?1049lX"))
+	if got := screen.Lines()[9]; got[39] != 'X' {
+		t.Errorf("Expected X in the bottom-right cell, got %q", got)
+	}
+
+	// ESC 7 / ESC 8 and CSI s / CSI u restore the clamped position too
+	screen.Write([]byte("\x1b[10;40H\x1b7\x1b[s"))
+	screen.Resize(20, 5)
+	screen.Write([]byte("\x1b8Y\x1b[uZ"))
+	if got := screen.Lines()[4]; got[19] != 'Z' {
+		t.Errorf("Expected Z in the bottom-right cell, got %q", got)
+	}
+}
+
+// TestShellKeyBytes verifies key presses are translated to terminal input
+func TestShellKeyBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    tea.KeyMsg
+		appKey bool
+		want   string
+	}{
+		{"runes", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ls")}, false, "ls"},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, false, "\r"},
+		{"ctrl+c", tea.KeyMsg{Type: tea.KeyCtrlC}, false, "\x03"},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, false, "\x1b"},
+		{"backspace", tea.KeyMsg{Type: tea.KeyBackspace}, false, "\x7f"},
+		{"up", tea.KeyMsg{Type: tea.KeyUp}, false, "\x1b[A"},
+		{"up (application mode)", tea.KeyMsg{Type: tea.KeyUp}, true, "\x1bOA"},
+		{"alt+b", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}, false, "\x1bb"},
+	}
+
+	for _, tt := range tests {
+		if got := string(shellKeyBytes(tt.msg, tt.appKey)); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestShellCloseKey verifies Ctrl+] leaves the shell popup
+func TestShellCloseKey(t *testing.T) {
+	model := Model{
+		currentView: viewShell,
+		shellScreen: newTerminalScreen(10, 3),
+	}
+
+	updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	m := updatedModel.(Model)
+
+	if m.currentView != viewList {
+		t.Error("Expected Ctrl+] to return to the list view")
+	}
+	if cmd != nil {
+		t.Error("Expected nothing to hang up before the session attached")
+	}
+
+	// An attached shell is hung up so it doesn't keep running in the container
+	model.shellExecID = "exec1"
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket}); cmd == nil {
+		t.Error("Expected Ctrl+] to hang up the attached shell")
+	}
+}
+
+// TestShellDropsStaleSession verifies a session from a shell that was
+// closed before it attached is shut instead of taking over the current one
+func TestShellDropsStaleSession(t *testing.T) {
+	model := Model{
+		currentView:    viewShell,
+		shellScreen:    newTerminalScreen(10, 3),
+		shellSessionID: 2,
+	}
+
+	updatedModel, _ := model.Update(shellReadyMsg{sessionID: 1, err: errors.New("no such container")})
+	model = updatedModel.(Model)
+	if model.currentView != viewShell || model.statusMsg != "" {
+		t.Errorf("Expected a stale failure to be ignored, got view %d status %q", model.currentView, model.statusMsg)
+	}
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	updatedModel, _ = model.Update(shellReadyMsg{sessionID: 1, execID: "old", conn: &types.HijackedResponse{Conn: local}})
+	model = updatedModel.(Model)
+	if model.shellExecID != "" || model.shellConn != nil {
+		t.Error("Expected a stale session not to be attached")
+	}
+	if _, err := remote.Write([]byte("x")); err == nil {
+		t.Error("Expected the stale session's connection to be closed")
+	}
+}
+
+// TestLogBufferEvictsOldest verifies the ring buffer keeps only the newest lines
+func TestLogBufferEvictsOldest(t *testing.T) {
+	buf := newLogBuffer(3)
+	for _, text := range []string{"a", "b", "c", "d", "e"} {
+		buf.Append(logLine{text: text})
+	}
+
+	if buf.Len() != 3 {
+		t.Fatalf("Expected 3 lines, got %d", buf.Len())
+	}
+	if buf.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped lines, got %d", buf.Dropped())
+	}
+	for i, want := range []string{"c", "d", "e"} {
+		if got := buf.Line(i).text; got != want {
+			t.Errorf("Line %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+// TestLogStreamBatches verifies the stream reader splits lines and batches them
+func TestLogStreamBatches(t *testing.T) {
+	stream := newLogStream(context.Background(), []io.ReadCloser{io.NopCloser(strings.NewReader("one\r\ntwo\nthree"))}, []logSource{{tty: true}}, false)
+
+	var got []string
+	for {
+		msg := waitForLogLines(1, stream)()
+		if lines, ok := msg.(logLinesMsg); ok {
+			for _, l := range lines.lines {
+				got = append(got, l.text)
+			}
+			continue
+		}
+		if _, ok := msg.(logsStreamEndedMsg); !ok {
+			t.Fatalf("Unexpected message %T", msg)
+		}
+		break
+	}
+
+	if strings.Join(got, ",") != "one,two,three" {
+		t.Errorf("Expected lines one,two,three, got %q", got)
+	}
+}
+
+// TestLogsPauseResume verifies pausing stops reading and resuming restarts it
+func TestLogsPauseResume(t *testing.T) {
+	model := Model{
+		currentView:  viewLogs,
+		logsBuffer:   newLogBuffer(10),
+		logsStream:   &logStream{lines: make(chan logLine)},
+		logsStreamID: 1,
+	}
+
+	updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m := updatedModel.(Model)
+	if !m.logsPaused || cmd != nil {
+		t.Error("Expected pause to stop scheduling reads")
+	}
+
+	// Lines that were already in flight are still applied while paused
+	updatedModel, cmd = m.Update(logLinesMsg{streamID: 1, lines: []logLine{{text: "hello"}}})
+	m = updatedModel.(Model)
+	if m.logsBuffer.Len() != 1 || cmd != nil {
+		t.Error("Expected in-flight lines to be buffered without scheduling another read")
+	}
+
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updatedModel.(Model)
+	if m.logsPaused || cmd == nil {
+		t.Error("Expected resume to schedule a read")
+	}
+
+	// Lines from a stale stream are ignored
+	updatedModel, _ = m.Update(logLinesMsg{streamID: 0, lines: []logLine{{text: "stale"}}})
+	m = updatedModel.(Model)
+	if m.logsBuffer.Len() != 1 {
+		t.Error("Expected lines from a stale stream to be dropped")
+	}
+}
+
+// newTestLogsModel returns a logs view model holding the given lines
+func newTestLogsModel(lines ...string) Model {
+	model := Model{
+		currentView: viewLogs,
+		logsBuffer:  newLogBuffer(100),
+		logsFollow:  true,
+		logsTail:    defaultLogsTail,
+		width:       80,
+		height:      15, // Page height of 5
+	}
+	for _, text := range lines {
+		model.logsBuffer.Append(logLine{text: text})
+	}
+	return model
+}
+
+// TestLogSegments verifies truncation and wrapping of long log lines
+func TestLogSegments(t *testing.T) {
+	long := strings.Repeat("x", 45)
+
+	if got := logSegments(long, 40, false); len(got) != 1 || !strings.HasSuffix(got[0], "...") || len(got[0]) != 40 {
+		t.Errorf("Expected one truncated segment, got %q", got)
+	}
+	if got := logSegments(long, 40, true); len(got) != 2 || len(got[1]) != 5 {
+		t.Errorf("Expected two wrapped segments, got %q", got)
+	}
+	if got := logSegments("short", 40, true); len(got) != 1 || got[0] != "short" {
+		t.Errorf("Expected short line unchanged, got %q", got)
+	}
+}
+
+// TestLogsPagerNavigation verifies scrolling leaves and G re-enters follow mode
+func TestLogsPagerNavigation(t *testing.T) {
+	model := newTestLogsModel("1", "2", "3", "4", "5", "6", "7", "8", "9", "10")
+
+	if start := model.logsStart(model.logsVisibleLines()); start != 5 {
+		t.Fatalf("Expected following view to start at line 5, got %d", start)
+	}
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m := updatedModel.(Model)
+	if m.logsFollow || m.logsStart(m.logsVisibleLines()) != 0 {
+		t.Errorf("Expected g to jump to the top, got start %d follow %v", m.logsStart(m.logsVisibleLines()), m.logsFollow)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = updatedModel.(Model)
+	if !m.logsFollow {
+		t.Error("Expected paging to the end to resume following")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updatedModel.(Model)
+	if m.logsFollow || m.logsStart(m.logsVisibleLines()) != 4 {
+		t.Errorf("Expected scrolling up to stop following at line 4, got %d", m.logsStart(m.logsVisibleLines()))
+	}
+
+	// New lines don't move a scrolled view, even as old lines are evicted
+	m.logsBuffer = newLogBuffer(10)
+	for i := 1; i <= 10; i++ {
+		m.logsBuffer.Append(logLine{text: fmt.Sprint(i)})
+	}
+	m.logsTop = 4
+	m.logsBuffer.Append(logLine{text: "11"}, logLine{text: "12"})
+	if got := m.logsBuffer.Line(m.logsStart(m.logsVisibleLines())).text; got != "5" {
+		t.Errorf("Expected view to stay on line 5, got %q", got)
+	}
+}
+
+// TestLogsSearch verifies regex search jumps between matches
+func TestLogsSearch(t *testing.T) {
+	model := newTestLogsModel("info start", "error: one", "info", "error: two", "info end", "a", "b", "c")
+	model.logsFollow = false
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m := updatedModel.(Model)
+	for _, r := range "err.r" {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updatedModel.(Model)
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+
+	if m.logsSearch == nil || m.logsStart(m.logsVisibleLines()) != 1 {
+		t.Fatalf("Expected search to jump to line 1, got %d", m.logsStart(m.logsVisibleLines()))
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updatedModel.(Model)
+	if m.logsStart(m.logsVisibleLines()) != 3 {
+		t.Errorf("Expected n to jump to line 3, got %d", m.logsStart(m.logsVisibleLines()))
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m = updatedModel.(Model)
+	if m.logsStart(m.logsVisibleLines()) != 1 {
+		t.Errorf("Expected N to jump back to line 1, got %d", m.logsStart(m.logsVisibleLines()))
+	}
+
+	highlighted := highlightMatches("an error here", m.logsSearch, lipgloss.NewStyle(), lipgloss.NewStyle().Bold(true))
+	if !strings.Contains(highlighted, "error") || !strings.HasPrefix(highlighted, "an ") {
+		t.Errorf("Unexpected highlight output %q", highlighted)
+	}
+}
+
+// TestLogsTailAndSinceValidation verifies tail/since values are checked before restarting
+func TestLogsTailAndSinceValidation(t *testing.T) {
+	for _, valid := range []string{"all", "0", "500"} {
+		if err := validateLogsTail(valid); err != nil {
+			t.Errorf("Expected tail %q to be valid: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"-1", "lots"} {
+		if err := validateLogsTail(invalid); err == nil {
+			t.Errorf("Expected tail %q to be invalid", invalid)
+		}
+	}
+	for _, valid := range []string{"", "10m", "2026-01-15T18:30:00Z", "1700000000"} {
+		if err := validateLogsSince(valid); err != nil {
+			t.Errorf("Expected since %q to be valid: %v", valid, err)
+		}
+	}
+	if err := validateLogsSince("yesterday"); err == nil {
+		t.Error("Expected since 'yesterday' to be invalid")
+	}
+}
+
+// TestLogStreamDemultiplexes verifies stdout and stderr frames are split and tagged
+func TestLogStreamDemultiplexes(t *testing.T) {
+	var raw bytes.Buffer
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("out one\nout "))
+	stdcopy.NewStdWriter(&raw, stdcopy.Stderr).Write([]byte("err one\n"))
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("two\n"))
+
+	stream := newLogStream(context.Background(), []io.ReadCloser{io.NopCloser(&raw)}, []logSource{{}}, false)
+
+	var got []logLine
+	for line := range stream.lines {
+		got = append(got, line)
+	}
+
+	expected := []logLine{
+		{text: "out one"},
+		{text: "err one", stderr: true},
+		{text: "out two"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d lines, got %+v", len(expected), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Line %d: expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+}
+
+// TestLogsStreamFilter verifies the stream toggle hides lines from the pager
+func TestLogsStreamFilter(t *testing.T) {
+	model := newTestLogsModel()
+	model.logsBuffer.Append(
+		logLine{text: "out"},
+		logLine{text: "err", stderr: true},
+		logLine{text: "out again"},
+	)
+
+	expectedCounts := []int{2, 1, 3} // stdout, stderr, both
+	for i, want := range expectedCounts {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+		model = updatedModel.(Model)
+		if got := len(model.logsVisibleLines()); got != want {
+			t.Errorf("Toggle %d (%s): expected %d lines, got %d", i+1, model.logsStreams, want, got)
+		}
+	}
+}
+
+// TestLogBufferInterleavesByTime verifies timestamped lines from several
+// sources are kept in time order regardless of arrival order
+func TestLogBufferInterleavesByTime(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buffer := newLogBuffer(10)
+	buffer.Append(
+		logLine{text: "a1", source: 0, time: base},
+		logLine{text: "a3", source: 0, time: base.Add(3 * time.Second)},
+		logLine{text: "b2", source: 1, time: base.Add(2 * time.Second)},
+		logLine{text: "b4", source: 1, time: base.Add(4 * time.Second)},
+	)
+
+	var got []string
+	for i := 0; i < buffer.Len(); i++ {
+		got = append(got, buffer.Line(i).text)
+	}
+	if strings.Join(got, ",") != "a1,b2,a3,b4" {
+		t.Errorf("Expected lines in time order, got %v", got)
+	}
+}
+
+// TestLogsAnchorKeptOnInsert verifies a scrolled merged view keeps showing
+// the same line when an older line is inserted above it
+func TestLogsAnchorKeptOnInsert(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	model := newTestLogsModel()
+	model.logsSources = []logSource{{id: "a", name: "web"}, {id: "b", name: "db"}}
+	model.logsBuffer.Append(
+		logLine{text: "a1", source: 0, time: base},
+		logLine{text: "a3", source: 0, time: base.Add(3 * time.Second)},
+		logLine{text: "a4", source: 0, time: base.Add(4 * time.Second)},
+	)
+	model.logsFollow = false
+	model.logsTop = 1
+
+	updatedModel, _ := model.Update(logLinesMsg{
+		streamID: model.logsStreamID,
+		lines:    []logLine{{text: "b2", source: 1, time: base.Add(2 * time.Second)}},
+	})
+	model = updatedModel.(Model)
+	if got := model.logsBuffer.Line(model.logsTop - model.logsBuffer.Dropped()).text; got != "a3" {
+		t.Errorf("Expected top line to stay a3, got %s", got)
+	}
+	if view := model.View(); !strings.Contains(view, "timestamps") {
+		t.Errorf("Expected timestamps indicator in merged view:\n%s", view)
+	}
+}
+
+// TestLogsSourceToggle verifies number keys hide and show sources in the merged view
+func TestLogsSourceToggle(t *testing.T) {
+	model := newTestLogsModel()
+	model.logsSources = []logSource{{id: "a", name: "web"}, {id: "b", name: "db"}}
+	model.logsBuffer.Append(
+		logLine{text: "web line", source: 0},
+		logLine{text: "db line", source: 1},
+		logLine{text: "web again", source: 0},
+	)
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	model = updatedModel.(Model)
+	if !model.logsSources[1].hidden {
+		t.Fatal("Expected second source to be hidden")
+	}
+	if got := len(model.logsVisibleLines()); got != 2 {
+		t.Errorf("Expected 2 visible lines with db hidden, got %d", got)
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "Merged Logs: web, db") || !strings.Contains(view, "web | web line") {
+		t.Errorf("Expected merged title and source prefix in view:\n%s", view)
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	model = updatedModel.(Model)
+	if got := len(model.logsVisibleLines()); got != 3 {
+		t.Errorf("Expected 3 visible lines after re-enabling db, got %d", got)
+	}
+}
+
+// TestSelectionOpensMergedLogs verifies space selects containers and logs
+// are opened for the whole selection
+func TestSelectionOpensMergedLogs(t *testing.T) {
+	model := Model{
+		ctx:         context.Background(),
+		currentView: viewList,
+		containers: []containerInfo{
+			{ID: "aaa", Name: "web", State: "running"},
+			{ID: "bbb", Name: "db", State: "running"},
+			{ID: "ccc", Name: "cache", State: "running"},
+		},
+		width:  100,
+		height: 30,
+	}
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	model = updatedModel.(Model)
+	model.cursor = 2
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	model = updatedModel.(Model)
+	if len(model.selected) != 2 {
+		t.Fatalf("Expected 2 selected containers, got %d", len(model.selected))
+	}
+
+	updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	model = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected a command to open the log streams")
+	}
+	if len(model.logsSources) != 2 || model.logsSources[0].name != "web" || model.logsSources[1].name != "cache" {
+		t.Errorf("Expected merged sources web and cache, got %+v", model.logsSources)
+	}
+	model.stopLogs()
+}
+
+// TestCalculateStats verifies resource usage is computed the way `docker stats` does
+func TestCalculateStats(t *testing.T) {
+	var v container.StatsResponse
+	v.CPUStats.CPUUsage.TotalUsage = 400
+	v.PreCPUStats.CPUUsage.TotalUsage = 200
+	v.CPUStats.SystemUsage = 2000
+	v.PreCPUStats.SystemUsage = 1000
+	v.CPUStats.OnlineCPUs = 4
+	v.MemoryStats.Usage = 300
+	v.MemoryStats.Limit = 1000
+	v.MemoryStats.Stats = map[string]uint64{"inactive_file": 100}
+	v.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: 10, TxBytes: 20},
+		"eth1": {RxBytes: 1, TxBytes: 2},
+	}
+	v.BlkioStats.IoServiceBytesRecursive = []container.BlkioStatEntry{
+		{Op: "Read", Value: 5},
+		{Op: "write", Value: 7},
+		{Op: "read", Value: 5},
+	}
+
+	sample := calculateStats(&v)
+	if sample.cpuPercent != 80 {
+		t.Errorf("Expected 80%% CPU, got %v", sample.cpuPercent)
+	}
+	if sample.memUsage != 200 || sample.memPercent != 20 {
+		t.Errorf("Expected 200 bytes (20%%) memory without cache, got %v (%v%%)", sample.memUsage, sample.memPercent)
+	}
+	if sample.netRx != 11 || sample.netTx != 22 {
+		t.Errorf("Expected net I/O 11/22, got %v/%v", sample.netRx, sample.netTx)
+	}
+	if sample.blockRead != 10 || sample.blockWrite != 7 {
+		t.Errorf("Expected block I/O 10/7, got %v/%v", sample.blockRead, sample.blockWrite)
+	}
+}
+
+// TestFormatBytes verifies binary and decimal byte formatting
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n      float64
+		binary bool
+		want   string
+	}{
+		{0, false, "0B"},
+		{512, false, "512B"},
+		{1234, false, "1.23kB"},
+		{5 * 1000 * 1000, false, "5MB"},
+		{1536 * 1024, true, "1.5MiB"},
+		{2 * 1024 * 1024 * 1024, true, "2GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n, tt.binary); got != tt.want {
+			t.Errorf("formatBytes(%v, %v) = %q, want %q", tt.n, tt.binary, got, tt.want)
+		}
+	}
+}
+
+// TestStatsSortKeepsCursor verifies sorting by CPU reorders the list and
+// keeps the cursor on the same container
+func TestStatsSortKeepsCursor(t *testing.T) {
+	containers := []containerInfo{
+		{ID: "aaa", Name: "idle", State: "running"},
+		{ID: "bbb", Name: "busy", State: "running"},
+		{ID: "ccc", Name: "medium", State: "running"},
+	}
+	model := Model{
+		currentView:   viewList,
+		allContainers: containers,
+		containers:    containers,
+		showStats:     true,
+		statsSort:     statsSortCPU,
+		stats:         map[string]statsSample{},
+		statsStreams: map[string]*statsStream{
+			"aaa": {}, "bbb": {}, "ccc": {},
+		},
+		width:  200,
+		height: 30,
+	}
+
+	for id, cpu := range map[string]float64{"aaa": 1, "bbb": 90, "ccc": 40} {
+		updatedModel, _ := model.Update(statsMsg{id: id, stream: model.statsStreams[id], sample: statsSample{cpuPercent: cpu}})
+		model = updatedModel.(Model)
+	}
+
+	var order []string
+	for _, c := range model.containers {
+		order = append(order, c.Name)
+	}
+	if strings.Join(order, ",") != "busy,medium,idle" {
+		t.Errorf("Expected containers sorted by CPU, got %v", order)
+	}
+	if model.containers[model.cursor].ID != "aaa" {
+		t.Errorf("Expected cursor to stay on idle, got %s", model.containers[model.cursor].Name)
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "CPU % ▼") || !strings.Contains(view, "90.00%") {
+		t.Errorf("Expected sorted CPU column in view:\n%s", view)
+	}
+}
+
+// TestStatsEndedIgnoresStaleStream verifies a closed stream only removes
+// its own entry
+func TestStatsEndedIgnoresStaleStream(t *testing.T) {
+	current := &statsStream{}
+	model := Model{
+		stats:        map[string]statsSample{"aaa": {cpuPercent: 5}},
+		statsStreams: map[string]*statsStream{"aaa": current},
+	}
+
+	updatedModel, _ := model.Update(statsEndedMsg{id: "aaa", stream: &statsStream{}})
+	model = updatedModel.(Model)
+	if model.statsStreams["aaa"] != current {
+		t.Error("Expected stale stream end to be ignored")
+	}
+
+	updatedModel, _ = model.Update(statsEndedMsg{id: "aaa", stream: current})
+	model = updatedModel.(Model)
+	if _, ok := model.statsStreams["aaa"]; ok {
+		t.Error("Expected current stream to be removed")
+	}
+	if _, ok := model.stats["aaa"]; ok {
+		t.Error("Expected sample to be removed with its stream")
+	}
+}
+
+// TestSearchCommandFilterSyncsStats verifies the h command stops the stats
+// streams of the containers it hides, as the h key does
+func TestSearchCommandFilterSyncsStats(t *testing.T) {
+	cancelled := false
+	stream := &statsStream{cancel: func() { cancelled = true }}
+	model := Model{
+		showStats: true,
+		allContainers: []containerInfo{
+			{ID: "aaa", Name: "k8s_pod", State: "running"},
+		},
+		stats:        map[string]statsSample{"aaa": {cpuPercent: 5}},
+		statsStreams: map[string]*statsStream{"aaa": stream},
+	}
+	model.filterContainers()
+
+	if cmd := model.executeSearchCommand("h"); cmd == nil {
+		t.Error("Expected a command to clear the status")
+	}
+	if !model.hideK8s || len(model.containers) != 0 {
+		t.Errorf("Expected k8s container hidden, got %+v", model.containers)
+	}
+	if !cancelled {
+		t.Error("Expected the hidden container's stats stream to be cancelled")
+	}
+	if _, ok := model.statsStreams["aaa"]; ok {
+		t.Error("Expected the hidden container's stats stream to be removed")
+	}
+}
+
+// TestSparkline verifies values are scaled to the peak and right-aligned
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 50, 100}, 5); got != "  ▁▄█" {
+		t.Errorf("Expected scaled, padded sparkline, got %q", got)
+	}
+	if got := sparkline([]float64{1, 2, 3, 4}, 2); got != "▆█" {
+		t.Errorf("Expected only the newest values, got %q", got)
+	}
+	if got := sparkline([]float64{0, 0}, 2); got != "▁▁" {
+		t.Errorf("Expected flat sparkline for zeros, got %q", got)
+	}
+}
+
+// TestStatsRates verifies cumulative counters become per-second rates
+func TestStatsRates(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []statsSample{
+		{netRx: 100, time: base},
+		{netRx: 300, time: base.Add(2 * time.Second)},
+		{netRx: 50, time: base.Add(3 * time.Second)}, // Counter reset on restart
+	}
+	rates := statsRates(history, func(s statsSample) float64 { return s.netRx })
+	if len(rates) != 2 || rates[0] != 100 || rates[1] != 0 {
+		t.Errorf("Expected rates [100 0], got %v", rates)
+	}
+}
+
+// TestStatsViewHistory verifies the stats view collects samples from its own
+// stream and ignores a stale one
+func TestStatsViewHistory(t *testing.T) {
+	stream := &statsStream{cancel: func() {}, detail: true}
+	model := Model{
+		currentView:     viewStats,
+		statsViewID:     "aaa",
+		statsViewName:   "web",
+		statsViewStream: stream,
+		statsViewCh:     make(chan tea.Msg),
+		width:           80,
+		height:          30,
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < statsHistoryLen+5; i++ {
+		updatedModel, _ := model.Update(statsMsg{id: "aaa", stream: stream, sample: statsSample{
+			cpuPercent: float64(i % 100),
+			pids:       7,
+			time:       base.Add(time.Duration(i) * time.Second),
+		}})
+		model = updatedModel.(Model)
+	}
+	if len(model.statsHistory) != statsHistoryLen {
+		t.Errorf("Expected history capped at %d, got %d", statsHistoryLen, len(model.statsHistory))
+	}
+
+	updatedModel, cmd := model.Update(statsMsg{id: "aaa", stream: &statsStream{detail: true}})
+	model = updatedModel.(Model)
+	if cmd != nil || len(model.statsHistory) != statsHistoryLen {
+		t.Error("Expected stale view stream sample to be dropped")
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "Container Stats: web") || !strings.Contains(view, "PIDs: 7") {
+		t.Errorf("Expected stats view with PIDs, got:\n%s", view)
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updatedModel.(Model)
+	if model.currentView != viewList || model.statsViewStream != nil {
+		t.Error("Expected ESC to close the stats view and its stream")
+	}
+}
+
+// TestNewContainerInfoComposeLabels verifies compose labels are read
+func TestNewContainerInfoComposeLabels(t *testing.T) {
+	info := newContainerInfo(container.Summary{
+		ID:    "abcdef1234567890",
+		Names: []string{"/shop-web-1"},
+		Labels: map[string]string{
+			composeProjectLabel: "shop",
+			composeServiceLabel: "web",
+		},
+	})
+	if info.Project != "shop" || info.Service != "web" {
+		t.Errorf("Expected project shop and service web, got %q/%q", info.Project, info.Service)
+	}
+}
+
+// newTestGroupedModel returns a grouped list with two projects and a standalone container
+func newTestGroupedModel() Model {
+	containers := []containerInfo{
+		{ID: "a1", Name: "solo", State: "running"},
+		{ID: "b1", Name: "shop-web-1", State: "running", Project: "shop", Service: "web"},
+		{ID: "c1", Name: "blog-db-1", State: "exited", Project: "blog", Service: "db"},
+		{ID: "b2", Name: "shop-db-1", State: "running", Project: "shop", Service: "db"},
+	}
+	model := Model{
+		currentView:    viewList,
+		allContainers:  containers,
+		groupByProject: true,
+		width:          120,
+		height:         30,
+	}
+	model.filterContainers()
+	model.cursor = 0 // blog-db-1, the first container row
+	return model
+}
+
+// TestGroupByProjectRows verifies projects get headers, sorted by name,
+// with standalone containers last
+func TestGroupByProjectRows(t *testing.T) {
+	model := newTestGroupedModel()
+
+	var got []string
+	for _, row := range model.listRows() {
+		if row.container < 0 {
+			got = append(got, "["+row.project+"]")
+		} else {
+			got = append(got, model.containers[row.container].Name)
+		}
+	}
+	want := "[blog],blog-db-1,[shop],shop-web-1,shop-db-1,solo"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Expected rows %s, got %s", want, strings.Join(got, ","))
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "▾ shop (2/2 running)") || !strings.Contains(view, "▾ blog (0/1 running)") {
+		t.Errorf("Expected project headers in view:\n%s", view)
+	}
+}
+
+// TestCollapseProjectAndActOnIt verifies a project header can be collapsed
+// and that actions on it target every container in the project
+func TestCollapseProjectAndActOnIt(t *testing.T) {
+	model := newTestGroupedModel()
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	// blog-db-1 -> [shop]
+	updatedModel, _ := model.Update(down)
+	model = updatedModel.(Model)
+	if model.cursorProject != "shop" {
+		t.Fatalf("Expected cursor on shop header, got %q", model.cursorProject)
+	}
+
+	targets := model.actionTargets()
+	if len(targets) != 2 || targets[0].Project != "shop" || targets[1].Project != "shop" {
+		t.Errorf("Expected both shop containers as targets, got %+v", targets)
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updatedModel.(Model)
+	if !model.collapsed["shop"] || len(model.listRows()) != 4 {
+		t.Errorf("Expected shop collapsed to 4 rows, got %d", len(model.listRows()))
+	}
+
+	// The next row after the collapsed header is the standalone container
+	updatedModel, _ = model.Update(down)
+	model = updatedModel.(Model)
+	if model.cursorProject != "" || model.containers[model.cursor].Name != "solo" {
+		t.Errorf("Expected cursor on solo, got %q", model.containers[model.cursor].Name)
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	model = updatedModel.(Model)
+	if !model.confirmingDestroy || !strings.Contains(model.statusMsg, "all 2 containers in project 'shop'") {
+		t.Errorf("Expected project destroy confirmation, got %q", model.statusMsg)
+	}
+}
+
+// newTestSelectionModel returns a list of three running containers
+func newTestSelectionModel() Model {
+	containers := []containerInfo{
+		{ID: "aaa", Name: "web", Image: "nginx", State: "running"},
+		{ID: "bbb", Name: "db", Image: "postgres", State: "running"},
+		{ID: "ccc", Name: "cache", Image: "redis", State: "running"},
+	}
+	return Model{
+		currentView:   viewList,
+		allContainers: containers,
+		containers:    containers,
+		eventsActive:  true,
+		width:         120,
+		height:        30,
+	}
+}
+
+// TestSelectAllAndByFilter verifies select-all toggling and select-by-filter
+func TestSelectAllAndByFilter(t *testing.T) {
+	model := newTestSelectionModel()
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if got := len(model.selectedContainers()); got != 3 {
+		t.Errorf("Expected all 3 selected, got %d", got)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if got := len(model.selectedContainers()); got != 0 {
+		t.Errorf("Expected selection cleared, got %d", got)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	update(&model, tea.KeyMsg{Type: tea.KeyEnter})
+	selected := model.selectedContainers()
+	if len(selected) != 1 || selected[0].Name != "cache" {
+		t.Errorf("Expected cache selected by image filter, got %+v", selected)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if len(model.selectedContainers()) != 0 {
+		t.Error("Expected ESC to clear the selection")
+	}
+}
+
+// TestBulkOperationSummary verifies an operation on a selection runs per
+// container and reports each result in the status bar
+func TestBulkOperationSummary(t *testing.T) {
+	model := newTestSelectionModel()
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	model = updatedModel.(Model)
+
+	updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model = updatedModel.(Model)
+	if cmd == nil || model.bulkOp == nil {
+		t.Fatal("Expected a bulk stop operation")
+	}
+	if model.statusMsg != "Stopping 0/3: web …  db …  cache …" {
+		t.Errorf("Unexpected progress status %q", model.statusMsg)
+	}
+
+	opID := model.bulkOp.id
+	updatedModel, _ = model.Update(bulkResultMsg{opID: opID, id: "bbb"})
+	model = updatedModel.(Model)
+	if model.statusMsg != "Stopping 1/3: web …  db ✓  cache …" {
+		t.Errorf("Unexpected progress status %q", model.statusMsg)
+	}
+
+	// Results from an earlier operation are ignored
+	updatedModel, _ = model.Update(bulkResultMsg{opID: opID - 1, id: "aaa"})
+	model = updatedModel.(Model)
+
+	updatedModel, _ = model.Update(bulkResultMsg{opID: opID, id: "aaa"})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(bulkResultMsg{opID: opID, id: "ccc", err: fmt.Errorf("boom")})
+	model = updatedModel.(Model)
+	if model.statusMsg != "Stopped 2/3: web ✓  db ✓  cache ✗ (cache: boom)" {
+		t.Errorf("Unexpected final status %q", model.statusMsg)
+	}
+	if model.bulkOp != nil {
+		t.Error("Expected bulk operation to be finished")
+	}
+}
+
+// TestBatchedDestroyConfirmation verifies one confirmation lists every selected name
+func TestBatchedDestroyConfirmation(t *testing.T) {
+	model := newTestSelectionModel()
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	model = updatedModel.(Model)
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	model = updatedModel.(Model)
+	if !model.confirmingDestroy || len(model.destroyTargets) != 3 {
+		t.Fatalf("Expected confirmation for 3 containers, got %d", len(model.destroyTargets))
+	}
+	if model.statusMsg != "⚠️  Destroy 3 containers (web, db, cache)? [y/n]" {
+		t.Errorf("Unexpected confirmation %q", model.statusMsg)
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	model = updatedModel.(Model)
+	if model.destroyTargets != nil {
+		t.Error("Expected targets cleared after cancelling")
+	}
+}
+
+// TestNewImageInfos verifies one row per tag, dangling detection and container usage
+func TestNewImageInfos(t *testing.T) {
+	containers := []containerInfo{
+		{Name: "web", ImageID: "sha256:aaaa1111bbbb2222cccc"},
+		{Name: "other", ImageID: "sha256:ffff"},
+	}
+
+	infos := newImageInfos(image.Summary{
+		ID:       "sha256:aaaa1111bbbb2222cccc",
+		RepoTags: []string{"nginx:latest", "nginx:1.27"},
+		Size:     1000,
+		Created:  1700000000,
+	}, containers)
+	if len(infos) != 2 || infos[0].Ref != "nginx:latest" || infos[1].Ref != "nginx:1.27" {
+		t.Fatalf("Expected a row per tag, got %+v", infos)
+	}
+	if infos[0].ID != "aaaa1111bbbb" || infos[0].Dangling {
+		t.Errorf("Expected short ID and not dangling, got %+v", infos[0])
+	}
+	if len(infos[0].Containers) != 1 || infos[0].Containers[0] != "web" {
+		t.Errorf("Expected web to use the image, got %v", infos[0].Containers)
+	}
+
+	dangling := newImageInfos(image.Summary{ID: "sha256:dddd", RepoTags: []string{"<none>:<none>"}}, containers)
+	if len(dangling) != 1 || !dangling[0].Dangling || dangling[0].Ref != "<none>:<none>" {
+		t.Errorf("Expected one dangling row, got %+v", dangling)
+	}
+}
+
+// TestImagesViewActions verifies navigation, the tag prompt and remove confirmation
+func TestImagesViewActions(t *testing.T) {
+	model := Model{currentView: viewImages, imagesLoading: true, width: 120, height: 30}
+	updatedModel, _ := model.Update(imagesLoadedMsg{images: []imageInfo{
+		{ID: "aaa", Ref: "nginx:latest", Created: time.Now()},
+		{ID: "bbb", Ref: "<none>:<none>", Dangling: true, Created: time.Now()},
+	}})
+	model = updatedModel.(Model)
+	if model.imagesLoading || len(model.images) != 2 {
+		t.Fatal("Expected images to be loaded")
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "nginx:latest") || !strings.Contains(view, "DANGLING") {
+		t.Errorf("Expected image table in view:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	if !model.confirmingImageRemove || !model.imageRemoveForce ||
+		model.statusMsg != "⚠️  Force remove image '<none>:<none>'? [y/n]" {
+		t.Errorf("Expected force remove confirmation, got %q", model.statusMsg)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.confirmingImageRemove || model.currentView != viewImages {
+		t.Error("Expected ESC to cancel the confirmation and stay in the images view")
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("nginx:mine")})
+	if !model.imageTagPrompt || model.imageTagInput != "nginx:mine" {
+		t.Errorf("Expected tag prompt with input, got %q", model.imageTagInput)
+	}
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || model.imageTagPrompt {
+		t.Error("Expected enter to submit the tag")
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if model.currentView != viewList {
+		t.Error("Expected q to return to the container list")
+	}
+}
+
+// TestInspectReturnsToOrigin verifies the inspect view goes back to the view it was opened from
+func TestInspectReturnsToOrigin(t *testing.T) {
+	model := Model{currentView: viewImages}
+	updatedModel, _ := model.Update(inspectDataMsg{data: "{}", title: "🔍 Image Inspection: nginx", returnTo: viewImages})
+	model = updatedModel.(Model)
+	if model.currentView != viewInspect || !strings.Contains(model.View(), "Image Inspection: nginx") {
+		t.Fatal("Expected the image inspect view")
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updatedModel.(Model)
+	if model.currentView != viewImages {
+		t.Errorf("Expected to return to the images view, got %v", model.currentView)
+	}
+}
+
+// TestVolumesViewSizesAndUsers verifies volumes are matched with their
+// containers and DiskUsage sizes arriving in either order
+func TestVolumesViewSizesAndUsers(t *testing.T) {
+	info := newContainerInfo(container.Summary{
+		ID:    "abc",
+		Names: []string{"/db"},
+		Mounts: []container.MountPoint{
+			{Type: "volume", Name: "pgdata"},
+			{Type: "bind", Source: "/tmp"},
+		},
+	})
+	if len(info.Volumes) != 1 || info.Volumes[0] != "pgdata" {
+		t.Fatalf("Expected pgdata volume mount, got %v", info.Volumes)
+	}
+	if users := volumeUsers([]containerInfo{info}); len(users["pgdata"]) != 1 || users["pgdata"][0] != "db" {
+		t.Errorf("Expected db to mount pgdata, got %v", users)
+	}
+
+	model := Model{currentView: viewVolumes, volumesLoading: true, width: 120, height: 30}
+	updatedModel, _ := model.Update(volumeSizesMsg{sizes: map[string]int64{"pgdata": 2000000}})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(volumesLoadedMsg{volumes: []volumeInfo{
+		{Name: "cache", Driver: "local", Size: -1},
+		{Name: "pgdata", Driver: "local", Size: -1, Containers: []string{"db"}},
+	}})
+	model = updatedModel.(Model)
+	if model.volumes[1].Size != 2000000 || model.volumes[0].Size != -1 {
+		t.Errorf("Expected sizes that arrived first to be applied, got %+v", model.volumes)
+	}
+	updatedModel, _ = model.Update(volumeSizesMsg{sizes: map[string]int64{"pgdata": 2000000, "cache": 0}})
+	model = updatedModel.(Model)
+
+	if model.volumes[1].Size != 2000000 || model.volumes[0].Size != 0 {
+		t.Errorf("Expected sizes applied, got %+v", model.volumes)
+	}
+	view := model.View()
+	if !strings.Contains(view, "pgdata") || !strings.Contains(view, "2MB") {
+		t.Errorf("Expected volume table with sizes:\n%s", view)
+	}
+}
+
+// TestVolumeRemoveAndPruneConfirmation verifies remove and prune ask first
+func TestVolumeRemoveAndPruneConfirmation(t *testing.T) {
+	model := Model{
+		currentView: viewVolumes,
+		volumes: []volumeInfo{
+			{Name: "cache"},
+			{Name: "pgdata", Containers: []string{"db"}},
+			{Name: "scratch"},
+		},
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if !model.confirmingVolumeRemove || model.volumeToRemove != "pgdata" ||
+		model.statusMsg != "⚠️  Remove volume 'pgdata' (used by db)? [y/n]" {
+		t.Errorf("Expected remove confirmation for pgdata, got %q", model.statusMsg)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")}) // Ignored while confirming
+	if !model.confirmingVolumeRemove {
+		t.Error("Expected confirmation to ignore other keys")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.confirmingVolumeRemove || model.volumeToRemove != "" {
+		t.Error("Expected ESC to cancel removal")
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if !model.confirmingVolumePrune || model.statusMsg != "⚠️  Prune 2 unused volumes (cache, scratch)? [y/n]" {
+		t.Errorf("Expected prune confirmation listing unused volumes, got %q", model.statusMsg)
+	}
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil || model.confirmingVolumePrune {
+		t.Error("Expected y to start the prune")
+	}
+}
+
+// TestNetworksViewShowsAttachedContainers verifies network rows and attached container IPs
+func TestNetworksViewShowsAttachedContainers(t *testing.T) {
+	info := newContainerInfo(container.Summary{
+		ID:    "abc",
+		Names: []string{"/web"},
+		NetworkSettings: &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{
+			"backend": {IPAddress: "172.18.0.2"},
+		}},
+	})
+	if info.Networks["backend"] != "172.18.0.2" {
+		t.Fatalf("Expected backend IP, got %v", info.Networks)
+	}
+
+	n := newNetworkInfo(network.Summary{
+		Name:   "backend",
+		ID:     "0123456789abcdef",
+		Driver: "bridge",
+		Scope:  "local",
+		IPAM:   network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"}}},
+	})
+	model := Model{currentView: viewNetworks, allContainers: []containerInfo{info}, width: 120, height: 30}
+	updatedModel, _ := model.Update(networksLoadedMsg{networks: []networkInfo{n}})
+	model = updatedModel.(Model)
+
+	view := model.View()
+	for _, want := range []string{"0123456789ab", "172.18.0.0/16", "172.18.0.1", "web (172.18.0.2)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in networks view:\n%s", want, view)
+		}
+	}
+}
+
+// TestNetworkActions verifies connect needs a container and predefined networks can't be removed
+func TestNetworkActions(t *testing.T) {
+	model := Model{
+		currentView: viewNetworks,
+		networks:    []networkInfo{{Name: "backend"}, {Name: "bridge"}},
+	}
+
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}); cmd != nil || !strings.HasPrefix(model.statusMsg, "Error:") {
+		t.Errorf("Expected connect without a container to fail, got %q", model.statusMsg)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if model.confirmingNetworkRemove {
+		t.Error("Expected predefined network removal to be refused")
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if !model.confirmingNetworkRemove || model.statusMsg != "⚠️  Remove network 'backend'? [y/n]" {
+		t.Errorf("Expected remove confirmation, got %q", model.statusMsg)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if model.confirmingNetworkRemove || model.networkToRemove != "" {
+		t.Error("Expected n to cancel removal")
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("dev")})
+	if !model.networkCreatePrompt || model.networkCreateInput != "dev" {
+		t.Errorf("Expected create prompt with input, got %q", model.networkCreateInput)
+	}
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || model.networkCreatePrompt {
+		t.Error("Expected enter to create the network")
+	}
+}
+
+// TestNewDiskUsageRows verifies reclaimable space and prune candidates per category
+func TestNewDiskUsageRows(t *testing.T) {
+	rows := newDiskUsageRows(types.DiskUsage{
+		LayersSize: 1000,
+		Images: []*image.Summary{
+			{ID: "sha256:aaaaaaaaaaaaaaaa", RepoTags: []string{"nginx:latest"}, Size: 600, SharedSize: 100, Containers: 1},
+			{ID: "sha256:bbbbbbbbbbbbbbbb", RepoTags: []string{"redis:7"}, Size: 200},
+			{ID: "sha256:cccccccccccccccc", Size: 300},
+		},
+		Containers: []*container.Summary{
+			{ID: "1", Names: []string{"/web"}, State: "running", SizeRw: 10},
+			{ID: "2", Names: []string{"/old"}, State: "exited", SizeRw: 20},
+		},
+		Volumes: []*volume.Volume{
+			{Name: "pgdata", UsageData: &volume.UsageData{RefCount: 1, Size: 50}},
+			{Name: "scratch", UsageData: &volume.UsageData{Size: 70}},
+		},
+		BuildCache: []*build.CacheRecord{
+			{ID: "r1", Size: 5, InUse: true},
+			{ID: "r2", Size: 8, Description: "RUN make"},
+		},
+	})
+
+	images := rows[diskImages]
+	if images.Total != 3 || images.Active != 1 || images.Reclaimable != 500 {
+		t.Errorf("Unexpected images row: %+v", images)
+	}
+	if len(images.Candidates) != 1 || images.Candidates[0].Name != "cccccccccccc" {
+		t.Errorf("Expected only the dangling image as a prune candidate, got %+v", images.Candidates)
+	}
+	if c := rows[diskContainers]; c.Size != 30 || c.Reclaimable != 20 || len(c.Candidates) != 1 || c.Candidates[0].Name != "old" {
+		t.Errorf("Unexpected containers row: %+v", c)
+	}
+	if v := rows[diskVolumes]; v.Active != 1 || v.Reclaimable != 70 || len(v.Candidates) != 1 || v.Candidates[0].Name != "scratch" {
+		t.Errorf("Unexpected volumes row: %+v", v)
+	}
+	if b := rows[diskBuildCache]; b.Size != 13 || b.Reclaimable != 8 || b.Candidates[0].Name != "r2 RUN make" {
+		t.Errorf("Unexpected build cache row: %+v", b)
+	}
+}
+
+// TestDiskPrunePreviewConfirmation verifies a prune previews its candidates and asks first
+func TestDiskPrunePreviewConfirmation(t *testing.T) {
+	model := Model{currentView: viewDiskUsage, width: 120, height: 30}
+	updatedModel, _ := model.Update(diskUsageMsg{rows: []diskUsageRow{
+		{Category: diskImages, Type: "Images", Prune: "dangling images"},
+		{Category: diskContainers, Type: "Containers", Prune: "stopped containers",
+			Candidates: []pruneCandidate{{"old", 2000}, {"older", 3000}}},
+	}})
+	model = updatedModel.(Model)
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if model.confirmingDiskPrune || model.statusMsg != "Nothing to prune: no dangling images" {
+		t.Errorf("Expected nothing to prune for images, got %q", model.statusMsg)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if view := model.View(); !strings.Contains(view, "older") || !strings.Contains(view, "Prune preview: 2 stopped containers, 5kB") {
+		t.Errorf("Expected prune preview listing candidates:\n%s", view)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if !model.confirmingDiskPrune || model.statusMsg != "⚠️  Prune 2 stopped containers (5kB)? [y/n]" {
+		t.Errorf("Expected prune confirmation, got %q", model.statusMsg)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}) // Scrolls the preview while confirming
+	if model.diskCursor != 1 {
+		t.Errorf("Expected the category to stay selected while confirming, got %d", model.diskCursor)
+	}
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil || model.confirmingDiskPrune {
+		t.Error("Expected y to start the prune")
+	}
+}
+
+// TestDiskPrunePreviewScrolls verifies every candidate can be reviewed
+// before a prune is confirmed
+func TestDiskPrunePreviewScrolls(t *testing.T) {
+	var candidates []pruneCandidate
+	for i := range 10 {
+		candidates = append(candidates, pruneCandidate{fmt.Sprintf("volume-%02d", i), 1000})
+	}
+	model := Model{currentView: viewDiskUsage, width: 120, height: 23,
+		diskUsage: []diskUsageRow{{Category: diskVolumes, Type: "Local Volumes", Prune: "unused volumes", Candidates: candidates}}}
+
+	view := model.View()
+	if !strings.Contains(view, "volume-02") || strings.Contains(view, "volume-03") ||
+		!strings.Contains(view, "Showing 1-3 of 10 unused volumes (press p to review them all)") {
+		t.Errorf("Expected the first candidates and a hint:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	for range 20 {
+		update(&model, tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view = model.View()
+	if !strings.Contains(view, "volume-09") || strings.Contains(view, "volume-06") ||
+		!strings.Contains(view, "Showing 8-10 of 10 unused volumes (scroll with ↑/↓)") {
+		t.Errorf("Expected the last candidates after scrolling:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyPgUp})
+	if model.diskPreviewScroll != 4 {
+		t.Errorf("Expected a page up to scroll back 3, got %d", model.diskPreviewScroll)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if model.diskPreviewScroll != 0 {
+		t.Errorf("Expected a new confirmation to start at the top, got %d", model.diskPreviewScroll)
+	}
+}
+
+// TestApplyPullMessages verifies pull stream messages become per-layer progress
+func TestApplyPullMessages(t *testing.T) {
+	model := Model{currentView: viewPull, pullRef: "nginx:latest", pullID: 1, pullRunning: true, width: 120, height: 30}
+	apply := func(message string) {
+		var m pullMessage
+		if err := json.Unmarshal([]byte(message), &m); err != nil {
+			t.Fatal(err)
+		}
+		update(&model, pullMsg{id: 1, message: m})
+	}
+
+	apply(`{"status":"Pulling from library/nginx","id":"latest"}`)
+	apply(`{"status":"Pulling fs layer","progressDetail":{},"id":"a1"}`)
+	apply(`{"status":"Already exists","progressDetail":{},"id":"b2"}`)
+	apply(`{"status":"Downloading","progressDetail":{"current":2500000,"total":10000000},"progress":"[==>   ]","id":"a1"}`)
+
+	if len(model.pullLog) != 1 || model.pullLog[0] != "latest Pulling from library/nginx" {
+		t.Errorf("Expected the header in the log, got %v", model.pullLog)
+	}
+	if len(model.pullLayers) != 2 || model.pullLayers[0].Status != "Downloading" || model.pullLayers[0].Current != 2500000 {
+		t.Fatalf("Expected layer progress, got %+v", model.pullLayers)
+	}
+	if bar := progressBar(25, 100, 8); bar != "██░░░░░░" {
+		t.Errorf("Expected a quarter-filled bar, got %q", bar)
+	}
+	view := model.View()
+	if !strings.Contains(view, "2.5MB/10MB") || !strings.Contains(view, "1/2 layers complete") {
+		t.Errorf("Expected layer progress in view:\n%s", view)
+	}
+
+	// Messages from an earlier pull are dropped without waiting again
+	updatedModel, cmd := model.Update(pullEndedMsg{id: 0})
+	model = updatedModel.(Model)
+	if cmd != nil || !model.pullRunning {
+		t.Error("Expected a stale pull end to be ignored")
+	}
+}
+
+// TestPullCancel verifies ESC cancels a running pull before leaving the view
+func TestPullCancel(t *testing.T) {
+	cancelled := false
+	model := Model{
+		currentView: viewPull,
+		pullReturn:  viewImages,
+		pullRef:     "redis:7",
+		pullID:      3,
+		pullRunning: true,
+		pullCancel:  func() { cancelled = true },
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if !cancelled || model.currentView != viewPull {
+		t.Fatal("Expected the first ESC to cancel and stay in the pull view")
+	}
+	updatedModel, _ := model.Update(pullEndedMsg{id: 3, err: context.Canceled})
+	model = updatedModel.(Model)
+	if model.pullRunning || model.statusMsg != "Pull cancelled" {
+		t.Errorf("Expected the pull to end cancelled, got %q", model.statusMsg)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.currentView != viewImages {
+		t.Errorf("Expected ESC to return to the images view, got %v", model.currentView)
+	}
+}
+
+// TestParseRunFormAndPreview verifies form values become a container config and docker run command
+func TestParseRunFormAndPreview(t *testing.T) {
+	var values [runFieldCount]string
+	values[runImage] = "nginx:alpine"
+	values[runName] = "web"
+	values[runPorts] = "8080:80, 127.0.0.1:8443:443/tcp"
+	values[runEnv] = "GREETING=hello world"
+	values[runRestart] = "on-failure:3"
+	values[runCommand] = `sh -c 'echo "hi" && sleep 1'`
+	values[runLabels] = "team=web"
+
+	spec, err := parseRunForm(values)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(spec.Command) != 3 || spec.Command[2] != `echo "hi" && sleep 1` {
+		t.Errorf("Expected quoted command to stay one argument, got %q", spec.Command)
+	}
+
+	config, hostConfig, err := spec.containerConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if b := hostConfig.PortBindings["443/tcp"]; len(b) != 1 || b[0].HostIP != "127.0.0.1" || b[0].HostPort != "8443" {
+		t.Errorf("Expected 443/tcp bound to 127.0.0.1:8443, got %+v", b)
+	}
+	if hostConfig.RestartPolicy.Name != container.RestartPolicyOnFailure || hostConfig.RestartPolicy.MaximumRetryCount != 3 {
+		t.Errorf("Unexpected restart policy: %+v", hostConfig.RestartPolicy)
+	}
+	if config.Labels["team"] != "web" {
+		t.Errorf("Expected team label, got %v", config.Labels)
+	}
+
+	want := `docker run -d --name web -p 8080:80 -p 127.0.0.1:8443:443/tcp -e 'GREETING=hello world' ` +
+		`--restart on-failure:3 -l team=web nginx:alpine sh -c 'echo "hi" && sleep 1'`
+	if got := formatCommand(spec.dockerRunArgs()); got != want {
+		t.Errorf("Unexpected command:\n got: %s\nwant: %s", got, want)
+	}
+
+	values[runRestart] = "sometimes"
+	if _, err := parseRunForm(values); err == nil {
+		t.Error("Expected an invalid restart policy to be rejected")
+	}
+}
+
+// TestRunFormPortConflict verifies the form refuses host ports another container publishes
+func TestRunFormPortConflict(t *testing.T) {
+	model := Model{allContainers: []containerInfo{{Name: "proxy", Ports: []string{"8080:80/tcp"}}}}
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	model = updatedModel.(Model)
+	if model.currentView != viewRun {
+		t.Fatalf("Expected n to open the run form, got %v", model.currentView)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("nginx")})
+	update(&model, tea.KeyMsg{Type: tea.KeyTab})
+	update(&model, tea.KeyMsg{Type: tea.KeyTab})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8080:80")})
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || model.runSubmitting {
+		t.Fatal("Expected a port conflict to block creation")
+	}
+	if model.statusMsg != "Error: host port 8080/tcp is already published by proxy" {
+		t.Errorf("Unexpected status: %q", model.statusMsg)
+	}
+	if view := model.View(); !strings.Contains(view, "docker run -d -p 8080:80 nginx") {
+		t.Errorf("Expected command preview:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyCtrlU})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8081:80")})
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || !model.runSubmitting {
+		t.Error("Expected enter to create the container")
+	}
+
+	// A form opened from the images view goes back there
+	model.runReturn = viewImages
+	updatedModel, _ = model.Update(runResultMsg{true, "Started nginx"})
+	model = updatedModel.(Model)
+	if model.currentView != viewImages || model.runSubmitting {
+		t.Errorf("Expected to return to the images view, got %v", model.currentView)
+	}
+}
+
+// TestRunSpecFromInspect verifies the generated docker run command leaves out image defaults
+func TestRunSpecFromInspect(t *testing.T) {
+	pids := int64(100)
+	info := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			Name: "/web",
+			HostConfig: &container.HostConfig{
+				Binds: []string{"html:/usr/share/nginx/html:ro"},
+				PortBindings: nat.PortMap{
+					"80/tcp": {{HostPort: "8080"}},
+					"53/udp": {{HostIP: "127.0.0.1", HostPort: "5353"}},
+				},
+				NetworkMode:   "frontend",
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 2},
+				Resources:     container.Resources{Memory: 512 << 20, NanoCPUs: 1500000000, PidsLimit: &pids},
+			},
+		},
+		Config: &container.Config{
+			Image:  "nginx:alpine",
+			Env:    []string{"PATH=/usr/local/bin:/usr/bin", "MODE=prod"},
+			Cmd:    []string{"nginx", "-g", "daemon off;"},
+			Labels: map[string]string{"maintainer": "NGINX", "team": "web"},
+		},
+	}
+	defaults := imageDefaults{
+		Env:    []string{"PATH=/usr/local/bin:/usr/bin"},
+		Cmd:    []string{"nginx", "-g", "daemon off;"},
+		Labels: map[string]string{"maintainer": "NGINX"},
+	}
+
+	want := "docker run -d --name web -p 127.0.0.1:5353:53/udp -p 8080:80 -e MODE=prod " +
+		"-v html:/usr/share/nginx/html:ro --network frontend --restart on-failure:2 -l team=web " +
+		"--memory 512m --cpus 1.5 --pids-limit 100 nginx:alpine"
+	if got := formatCommand(runSpecFromInspect(info, defaults).dockerRunArgs()); got != want {
+		t.Errorf("Unexpected command:\n got: %s\nwant: %s", got, want)
+	}
+
+	// An overridden entrypoint resets the image command, so both are shown
+	info.Config.Entrypoint = []string{"/bin/sh", "-c"}
+	info.Config.Cmd = []string{"nginx -g 'daemon off;'"}
+	args := runSpecFromInspect(info, defaults).dockerRunArgs()
+	if got := formatCommand(args[len(args)-5:]); got != `--entrypoint /bin/sh nginx:alpine -c 'nginx -g '\''daemon off;'\'''` {
+		t.Errorf("Unexpected entrypoint handling: %s", got)
+	}
+}
+
+// TestComposeFile verifies exported services, named volumes, networks and depends_on hints
+func TestComposeFile(t *testing.T) {
+	got := composeFile([]composeService{
+		{
+			Name:          "web",
+			ContainerName: "web",
+			Spec: runSpec{
+				Image:   "nginx:alpine",
+				Ports:   []string{"8080:80"},
+				Env:     []string{"MODE=prod", "PASSWORD=pa$$w0rd"},
+				Volumes: []string{"html:/usr/share/nginx/html:ro", "/srv/conf:/etc/nginx/conf.d"},
+				Command: []string{"nginx", "-g", "daemon off;", "$HOME"},
+				Labels:  []string{"com.docker.compose.project=x", "team=web", "price=${cost}"},
+				Restart: "unless-stopped",
+			},
+			Networks: []string{"backend"},
+		},
+		{Name: "db", Spec: runSpec{Image: "postgres:16", Memory: 1 << 30}, Networks: []string{"backend"}},
+	})
+
+	want := `# Generated by lcm from container inspect data
+services:
+  web:
+    image: nginx:alpine
+    container_name: web
+    command: [nginx, "-g", "daemon off;", "$$HOME"]
+    ports:
+      - "8080:80"
+    environment:
+      - MODE=prod
+      - "PASSWORD=pa$$$$w0rd"
+    volumes:
+      - html:/usr/share/nginx/html:ro
+      - /srv/conf:/etc/nginx/conf.d
+    networks:
+      - backend
+    restart: unless-stopped
+    labels:
+      team: web
+      price: "$${cost}"
+    # Shares a network with db; uncomment the services this one depends on
+    # depends_on:
+    #   - db
+
+  db:
+    image: postgres:16
+    networks:
+      - backend
+    mem_limit: 1g
+    # Shares a network with web; uncomment the services this one depends on
+    # depends_on:
+    #   - web
+
+volumes:
+  html:
+    name: html
+
+networks:
+  backend:
+    name: backend
+`
+	if got != want {
+		t.Errorf("Unexpected compose file:\n%s", got)
+	}
+	if yamlString("yes") != `"yes"` || yamlString("8080") != `"8080"` || yamlString("a: b") != `"a: b"` || yamlString("key:") != `"key:"` {
+		t.Error("Expected ambiguous scalars to be quoted")
+	}
+}
+
+// TestExportPromptConfirmsOverwrite verifies exporting over an existing file asks first
+func TestExportPromptConfirmsOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	model := Model{containers: []containerInfo{{ID: "abc", Name: "web"}}}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")})
+	if !model.exportPrompt || model.exportPromptInput != "docker-compose.yml" || len(model.exportTargets) != 1 {
+		t.Fatalf("Expected export prompt for the cursor container, got %+v", model.exportTargets)
+	}
+
+	// A directory gets the default file name, which already exists here
+	if err := os.WriteFile(dir+"/docker-compose.yml", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	model.exportPromptInput = dir
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !model.confirmingExportOverwrite {
+		t.Fatalf("Expected overwrite confirmation, got %q", model.statusMsg)
+	}
+	if model.exportPath != dir+"/docker-compose.yml" {
+		t.Errorf("Unexpected export path %q", model.exportPath)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if model.confirmingExportOverwrite || model.statusMsg != "Export cancelled" {
+		t.Errorf("Expected n to cancel, got %q", model.statusMsg)
+	}
+}
+
+// TestRecreateConfigKeepsUnshownSettings verifies edits apply on top of the original configuration
+func TestRecreateConfigKeepsUnshownSettings(t *testing.T) {
+	info := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID: "0123456789abcdef",
+			HostConfig: &container.HostConfig{
+				Binds:      []string{"old:/data"},
+				Resources:  container.Resources{Memory: 256 << 20},
+				Privileged: true,
+			},
+		},
+		Config: &container.Config{Hostname: "0123456789ab", User: "app", Image: "api:1", Env: []string{"OLD=1"}},
+	}
+	spec := runSpec{Image: "api:2", Env: []string{"NEW=2"}, Ports: []string{"9000:9000"}, Restart: "always"}
+
+	config, hostConfig, err := recreateConfig(info, spec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Image != "api:2" || len(config.Env) != 1 || config.Env[0] != "NEW=2" || config.User != "app" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if config.Hostname != "" {
+		t.Errorf("Expected the default hostname to be dropped, got %q", config.Hostname)
+	}
+	if !hostConfig.Privileged || hostConfig.Memory != 256<<20 || len(hostConfig.Binds) != 0 ||
+		hostConfig.RestartPolicy.Name != container.RestartPolicyAlways || len(hostConfig.PortBindings) != 1 {
+		t.Errorf("Unexpected host config: %+v", hostConfig)
+	}
+	if info.Config.Image != "api:1" {
+		t.Error("Expected the original configuration to be left untouched")
+	}
+}
+
+// TestRecreateFormPrefillAndConfirm verifies the form starts from the container and asks before recreating
+func TestRecreateFormPrefillAndConfirm(t *testing.T) {
+	model := Model{allContainers: []containerInfo{{ID: "0123456789ab", Name: "api", Ports: []string{"9000:9000/tcp"}}}}
+	updatedModel, _ := model.Update(recreateLoadedMsg{target: recreateTarget{
+		ID:   "0123456789abcdef",
+		Name: "api",
+		Spec: runSpec{
+			Image:   "api:1",
+			Name:    "api",
+			Ports:   []string{"9000:9000"},
+			Env:     []string{"NO_PROXY=localhost,127.0.0.1"},
+			Command: []string{"serve", "--addr", ":9000 now"},
+		},
+	}})
+	model = updatedModel.(Model)
+
+	if model.currentView != viewRun || model.runRecreate == nil {
+		t.Fatal("Expected the recreate form to open")
+	}
+	if got := model.runForm[runEnv]; got != `NO_PROXY=localhost\,127.0.0.1` {
+		t.Errorf("Expected escaped comma in env, got %q", got)
+	}
+	spec, err := parseRunForm(model.runForm)
+	if err != nil || len(spec.Env) != 1 || spec.Env[0] != "NO_PROXY=localhost,127.0.0.1" || spec.Command[2] != ":9000 now" {
+		t.Errorf("Expected the form to round-trip, got %+v (%v)", spec, err)
+	}
+
+	// The container's own port doesn't conflict with itself
+	updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updatedModel.(Model)
+	if cmd != nil || !model.runConfirming || !strings.HasPrefix(model.statusMsg, "⚠️  Recreate 'api'") {
+		t.Fatalf("Expected recreate confirmation, got %q", model.statusMsg)
+	}
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updatedModel.(Model)
+	if model.runConfirming || model.currentView != viewRun || model.statusMsg != "Recreate cancelled" {
+		t.Errorf("Expected ESC to cancel and stay in the form, got %q", model.statusMsg)
+	}
+}
+
+// TestPausedFilterAndPause verifies paused containers can be hidden and pause only targets running ones
+func TestPausedFilterAndPause(t *testing.T) {
+	model := Model{allContainers: []containerInfo{
+		{ID: "a", Name: "web", State: "running"},
+		{ID: "b", Name: "db", State: "paused"},
+		{ID: "c", Name: "cache", State: "running"},
+		{ID: "d", Name: "worker", State: "exited"},
+	}}
+	model.filterContainers()
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if len(model.containers) != 3 || model.statusMsg != "Hiding paused containers" {
+		t.Fatalf("Expected the paused container hidden, got %d shown", len(model.containers))
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if len(model.containers) != 4 {
+		t.Fatalf("Expected paused containers shown again, got %d", len(model.containers))
+	}
+
+	// With a mixed selection only the running containers are paused
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}); cmd == nil || model.bulkOp == nil || len(model.bulkOp.targets) != 2 || model.bulkOp.past != "Paused" {
+		t.Fatalf("Expected a bulk pause of the two running containers, got %+v", model.bulkOp)
+	}
+
+	// A selection with nothing running unpauses the paused containers
+	model.selected = map[string]bool{"b": true, "d": true}
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}); cmd == nil || model.statusMsg != "Unpausing container..." {
+		t.Errorf("Expected unpause, got %q", model.statusMsg)
+	}
+
+	// Nothing to pause or unpause says why instead of "No container selected"
+	model.selected = map[string]bool{"d": true}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if model.statusMsg != "No running or paused containers in selection" {
+		t.Errorf("Expected the selection to be reported, got %q", model.statusMsg)
+	}
+	model.selected = nil
+	model.cursor = 3
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if model.statusMsg != "Cannot pause worker: container is exited" {
+		t.Errorf("Expected the cursor container's state to be reported, got %q", model.statusMsg)
+	}
+}
+
+// TestKillSignalPicker verifies choosing a signal before killing
+func TestKillSignalPicker(t *testing.T) {
+	model := Model{containers: []containerInfo{{ID: "a", Name: "web", State: "running"}}, width: 120, height: 40}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if !model.killPicker || killSignals[model.killSignal] != "SIGKILL" {
+		t.Fatal("Expected the picker to open on SIGKILL")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRight})
+	if killSignals[model.killSignal] != "SIGTERM" {
+		t.Errorf("Expected right to move to SIGTERM, got %s", killSignals[model.killSignal])
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	if killSignals[model.killSignal] != "SIGHUP" {
+		t.Errorf("Expected 4 to pick SIGHUP, got %s", killSignals[model.killSignal])
+	}
+	if view := model.View(); !strings.Contains(view, "Signal for 1 container") {
+		t.Errorf("Expected the signal picker in the view:\n%s", view)
+	}
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || model.killPicker {
+		t.Error("Expected enter to send the signal")
+	}
+	if model.statusMsg != "Sending SIGHUP to container..." {
+		t.Errorf("Unexpected status %q", model.statusMsg)
+	}
+}
+
+// TestTarRoundTrip tests archiving a local directory and extracting it again
+func TestTarRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "site")
+	if err := os.MkdirAll(filepath.Join(src, "css"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "index.html"), []byte("<h1>hi</h1>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "css", "app.css"), []byte("body{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	var written int64
+	if err := writeTar(&archive, src, func(n int64) { written += n }); err != nil {
+		t.Fatal(err)
+	}
+	if written != 17 {
+		t.Errorf("Expected 17 bytes reported while archiving, got %d", written)
+	}
+
+	dest := t.TempDir()
+	files, err := extractTar(&archive, dest, func(int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 2 {
+		t.Errorf("Expected 2 files extracted, got %d", files)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "site", "css", "app.css"))
+	if err != nil || string(data) != "body{}" {
+		t.Errorf("Unexpected app.css contents %q (%v)", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(dest, "site", "css", "app.css")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the file mode to be kept, got %v (%v)", info.Mode(), err)
+	}
+
+	// Entries escaping the destination are refused
+	var evil bytes.Buffer
+	tw := tar.NewWriter(&evil)
+	tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	if _, err := extractTar(&evil, dest, func(int64) {}); err == nil {
+		t.Error("Expected an entry outside the destination to be refused")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "escape.txt")); err == nil {
+		t.Error("Expected nothing to be written outside the destination")
+	}
+
+	// So are entries written through a symlink that leads out of it,
+	// whether as a file or as the source of a hard link
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("s"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []tar.Header{
+		{Name: "link/file.txt", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg},
+		{Name: "stolen", Linkname: "link/secret", Typeflag: tar.TypeLink},
+	} {
+		evil.Reset()
+		tw = tar.NewWriter(&evil)
+		tw.WriteHeader(&tar.Header{Name: "link", Linkname: outside, Mode: 0o777, Typeflag: tar.TypeSymlink})
+		tw.WriteHeader(&entry)
+		if entry.Size > 0 {
+			tw.Write([]byte("x"))
+		}
+		tw.Close()
+		if _, err := extractTar(&evil, t.TempDir(), func(int64) {}); err == nil {
+			t.Errorf("Expected %s through a symlink outside the destination to be refused", entry.Name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "file.txt")); err == nil {
+		t.Error("Expected nothing to be written through the symlink")
+	}
+}
+
+// TestCopyPromptStages tests the copy prompt from direction to local path
+func TestCopyPromptStages(t *testing.T) {
+	model := Model{containers: []containerInfo{{ID: "a", Name: "web", State: "running"}}, width: 120, height: 40}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if model.copyStage != copyStageDirection {
+		t.Fatal("Expected c to open the copy prompt")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if model.copyStage != copyStageContainerPath || !model.copyInto || model.copyInput != "/" {
+		t.Fatalf("Expected the container path prompt for copying in, got stage %d input %q", model.copyStage, model.copyInput)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("tmp")})
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("Expected enter to check the container path")
+	}
+
+	// A file can't be a destination directory, and a failed check keeps the prompt open
+	update(&model, copyStatMsg{path: "/tmp", stat: container.PathStat{Name: "tmp"}})
+	if model.copyStage != copyStageContainerPath || !strings.Contains(model.statusMsg, "not a directory") {
+		t.Errorf("Expected a non-directory to be refused, got stage %d status %q", model.copyStage, model.statusMsg)
+	}
+	update(&model, copyStatMsg{path: "/tmp", err: errors.New("no such file")})
+	if model.copyStage != copyStageContainerPath || !strings.Contains(model.statusMsg, "no such file") {
+		t.Errorf("Expected a stat error to keep the prompt open, got status %q", model.statusMsg)
+	}
+
+	update(&model, copyStatMsg{path: "/tmp", stat: container.PathStat{Name: "tmp", Mode: os.ModeDir | 0o755}})
+	if model.copyStage != copyStageLocalPath || model.copyContainerPath != "/tmp" {
+		t.Fatalf("Expected the local path prompt, got stage %d", model.copyStage)
+	}
+	if view := model.View(); !strings.Contains(view, "copy into web:/tmp") {
+		t.Errorf("Expected the local path prompt in the view:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(filepath.Join(t.TempDir(), "missing"))})
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || model.copyRunning {
+		t.Error("Expected a missing local source to be refused")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.copyStage != copyStageNone {
+		t.Error("Expected esc to close the copy prompt")
+	}
+}
+
+// TestFileListings tests parsing stat output and tar headers into directory entries
+func TestFileListings(t *testing.T) {
+	entries := parseStatListing("41ed|4096|1700000000|.\n41ed|4096|1700000000|..\n81a4|12|1700000000|nginx.conf\n41ed|4096|1700000000|conf.d\na1ff|7|1700000000|mime\n")
+	sortFileEntries(entries)
+	if len(entries) != 3 {
+		t.Fatalf("Expected . and .. to be skipped, got %+v", entries)
+	}
+	if entries[0].Name != "conf.d" || !entries[0].Mode.IsDir() {
+		t.Errorf("Expected the directory first, got %+v", entries[0])
+	}
+	if entries[1].Name != "mime" || entries[1].Mode&os.ModeSymlink == 0 || entries[1].Mode.Perm() != 0o777 {
+		t.Errorf("Expected mime to be a symlink, got %v", entries[1].Mode)
+	}
+	if entries[2].Mode.String() != "-rw-r--r--" || entries[2].Size != 12 {
+		t.Errorf("Unexpected nginx.conf entry %+v", entries[2])
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	tw.WriteHeader(&tar.Header{Name: "nginx/", Mode: 0o755, Typeflag: tar.TypeDir})
+	tw.WriteHeader(&tar.Header{Name: "nginx/conf.d/", Mode: 0o755, Typeflag: tar.TypeDir})
+	tw.WriteHeader(&tar.Header{Name: "nginx/conf.d/default.conf", Mode: 0o644, Size: 3, Typeflag: tar.TypeReg})
+	tw.Write([]byte("abc"))
+	tw.WriteHeader(&tar.Header{Name: "nginx/nginx.conf", Mode: 0o644, Size: 2, Typeflag: tar.TypeReg})
+	tw.Write([]byte("ok"))
+	tw.Close()
+	entries, err := tarListing(&archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name != "conf.d" || entries[1].Name != "nginx.conf" || entries[1].Size != 2 {
+		t.Errorf("Expected only the direct children, got %+v", entries)
+	}
+
+	if lines, note := previewLines([]byte("a\tb\nc\n"), 6); len(lines) != 2 || lines[0] != "a    b" || note != "" {
+		t.Errorf("Unexpected preview %q %q", lines, note)
+	}
+	if lines, note := previewLines([]byte{0x7f, 'E', 'L', 'F', 0}, 5); lines != nil || !strings.Contains(note, "Binary") {
+		t.Errorf("Expected a binary file note, got %q", note)
+	}
+	if lines, note := previewLines([]byte("héllo"[:2]), 100); len(lines) != 1 || lines[0] != "h" || !strings.Contains(note, "first") {
+		t.Errorf("Expected a truncated character to be dropped, got %q %q", lines, note)
+	}
+}
+
+// TestFilesBrowserNavigation tests moving through the file browser, previewing and downloading
+func TestFilesBrowserNavigation(t *testing.T) {
+	model := Model{
+		containers:     []containerInfo{{ID: "a", Name: "web", State: "exited"}},
+		currentView:    viewFiles,
+		filesContainer: containerInfo{ID: "a", Name: "web", State: "exited"},
+		filesPath:      "/etc",
+		width:          120,
+		height:         40,
+	}
+
+	update(&model, filesListedMsg{path: "/etc/nginx", entries: []fileEntry{
+		{Name: "conf.d", Mode: os.ModeDir | 0o755},
+		{Name: "nginx.conf", Mode: 0o644, Size: 1536},
+	}})
+	if model.filesPath != "/etc/nginx" || model.filesCursor != 0 {
+		t.Fatalf("Expected the listing to be shown, got path %s", model.filesPath)
+	}
+	view := model.View()
+	if !strings.Contains(view, "web:/etc/nginx (exited)") || !strings.Contains(view, "conf.d/") || !strings.Contains(view, "1.54kB") {
+		t.Errorf("Expected the listing in the view:\n%s", view)
+	}
+
+	// Going back up selects the directory just left
+	update(&model, filesListedMsg{path: "/etc", entries: []fileEntry{
+		{Name: "hosts", Mode: 0o644},
+		{Name: "nginx", Mode: os.ModeDir | 0o755},
+	}})
+	if model.filesCursor != 1 {
+		t.Errorf("Expected nginx to be selected after going up, got %d", model.filesCursor)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyUp})
+	update(&model, filesPreviewMsg{path: "/etc/hosts", lines: []string{"127.0.0.1 localhost"}})
+	if !model.filesPreviewing || !strings.Contains(model.View(), "127.0.0.1 localhost") {
+		t.Fatal("Expected the preview to be shown")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if model.copyStage != copyStageLocalPath || model.copyInto || model.copyContainerPath != "/etc/hosts" {
+		t.Errorf("Expected d to ask where to download /etc/hosts, got stage %d path %q", model.copyStage, model.copyContainerPath)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.filesPreviewing || model.currentView != viewFiles {
+		t.Error("Expected esc to close the prompt and then the preview")
+	}
+
+	// A listing superseded by a later request is dropped
+	model.filesID++
+	update(&model, filesListedMsg{id: model.filesID - 1, path: "/var"})
+	if model.filesPath != "/etc" {
+		t.Errorf("Expected a stale listing to be dropped, got path %s", model.filesPath)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if model.copyStage != copyStageLocalPath || !model.copyInto || model.copyContainerPath != "/etc" {
+		t.Errorf("Expected u to upload into /etc, got %q", model.copyContainerPath)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.currentView != viewList {
+		t.Error("Expected esc to return to the list")
+	}
+	update(&model, filesPreviewMsg{id: model.filesID, path: "/etc/hosts"})
+	if model.currentView != viewList || model.filesPreviewing {
+		t.Error("Expected a preview arriving after leaving the browser to be dropped")
+	}
+}
+
+// TestBuildDiffTree tests arranging ContainerDiff changes into a tree with counts
+func TestBuildDiffTree(t *testing.T) {
+	root := buildDiffTree([]container.FilesystemChange{
+		{Kind: container.ChangeModify, Path: "/var"},
+		{Kind: container.ChangeModify, Path: "/var/log"},
+		{Kind: container.ChangeAdd, Path: "/var/log/app.log"},
+		{Kind: container.ChangeAdd, Path: "/tmp/cache/a"},
+		{Kind: container.ChangeDelete, Path: "/etc/motd"},
+	})
+
+	if root.Counts != [3]int{2, 2, 1} {
+		t.Errorf("Expected 2 changed, 2 added and 1 deleted at the root, got %v", root.Counts)
+	}
+	var names []string
+	for _, child := range root.Children {
+		names = append(names, child.Name)
+	}
+	if strings.Join(names, ",") != "etc,tmp,var" {
+		t.Errorf("Expected sorted top-level directories, got %v", names)
+	}
+
+	// /tmp and /tmp/cache weren't reported themselves, only the file below them
+	tmp := root.Children[1]
+	if tmp.Changed || tmp.Counts != [3]int{0, 1, 0} || tmp.Children[0].Path != "/tmp/cache" {
+		t.Errorf("Unexpected /tmp node %+v", tmp)
+	}
+	log := root.Children[2].Children[0]
+	if !log.Changed || log.Kind != container.ChangeModify || log.Counts != [3]int{1, 1, 0} {
+		t.Errorf("Unexpected /var/log node %+v", log)
+	}
+}
+
+// TestDiffViewCollapse tests expanding and collapsing the diff tree
+func TestDiffViewCollapse(t *testing.T) {
+	changes := []container.FilesystemChange{
+		{Kind: container.ChangeModify, Path: "/etc"},
+		{Kind: container.ChangeModify, Path: "/etc/hosts"},
+		{Kind: container.ChangeModify, Path: "/var"},
+	}
+	for i := 0; i <= diffCollapseThreshold; i++ {
+		changes = append(changes, container.FilesystemChange{Kind: container.ChangeAdd, Path: fmt.Sprintf("/var/cache/%02d", i)})
+	}
+	model := Model{
+		containers:  []containerInfo{{ID: "a", Name: "web"}},
+		currentView: viewDiff,
+		diffLoading: true,
+		width:       120,
+		height:      40,
+	}
+
+	updatedModel, _ := model.Update(diffLoadedMsg{id: model.diffID, root: buildDiffTree(changes)})
+	model = updatedModel.(Model)
+
+	// /var holds too many changes, so it starts collapsed
+	if rows := model.diffRows(); len(rows) != 3 || rows[2].node.Path != "/var" {
+		t.Fatalf("Expected /etc, /etc/hosts and a collapsed /var, got %d rows", len(rows))
+	}
+	view := model.View()
+	if !strings.Contains(view, "Added 21  Changed 3  Deleted 0") || !strings.Contains(view, "▸ ") {
+		t.Errorf("Expected the totals and a collapsed marker in the view:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(&model, tea.KeyMsg{Type: tea.KeyEnter})
+	if rows := model.diffRows(); len(rows) != 4 {
+		t.Errorf("Expected enter to expand /var, got %d rows", len(rows))
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if rows := model.diffRows(); len(rows) != 3+1+diffCollapseThreshold+1 {
+		t.Errorf("Expected e to expand everything, got %d rows", len(rows))
+	}
+
+	// Left on a file moves to its directory, then collapses it
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	update(&model, tea.KeyMsg{Type: tea.KeyLeft})
+	if model.diffCursor != 0 {
+		t.Errorf("Expected left to move to /etc, got row %d", model.diffCursor)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyLeft})
+	if rows := model.diffRows(); !model.diffCollapsed["/etc"] || len(rows) != 2+1+diffCollapseThreshold+1 {
+		t.Errorf("Expected left to collapse /etc, got %d rows", len(rows))
+	}
+}
+
+// TestDiffDropsStaleResult verifies a diff still loading for one container
+// is not shown once the view is opened for another
+func TestDiffDropsStaleResult(t *testing.T) {
+	model := Model{
+		containers:  []containerInfo{{ID: "a", Name: "web"}, {ID: "b", Name: "db"}},
+		currentView: viewList,
+		width:       120,
+		height:      40,
+	}
+	model.openDiff()
+	stale := model.diffID
+	model.currentView = viewList
+	model.cursor = 1
+	model.openDiff()
+
+	updatedModel, _ := model.Update(diffLoadedMsg{id: stale, root: buildDiffTree([]container.FilesystemChange{
+		{Kind: container.ChangeAdd, Path: "/web.txt"},
+	})})
+	model = updatedModel.(Model)
+	if model.diffRoot != nil || !model.diffLoading {
+		t.Error("Expected the diff of web to be dropped while db's is loading")
+	}
+
+	updatedModel, _ = model.Update(diffLoadedMsg{id: model.diffID, root: buildDiffTree(nil)})
+	model = updatedModel.(Model)
+	if model.diffRoot == nil || model.diffLoading || model.diffContainer.Name != "db" {
+		t.Error("Expected the diff of db to be shown")
+	}
+}
+
+// TestSortTopProcesses tests sorting ContainerTop rows by CPU, memory and PID
+func TestSortTopProcesses(t *testing.T) {
+	titles := []string{"USER", "PID", "%CPU", "%MEM", "COMMAND"}
+	processes := [][]string{
+		{"root", "10", "0.5", "3.0", "nginx: master"},
+		{"www", "9", "12.0", "1.0", "nginx: worker"},
+		{"www", "100", "2.5", "7.5", "nginx: worker"},
+	}
+	pids := func() string {
+		var p []string
+		for _, row := range processes {
+			p = append(p, row[1])
+		}
+		return strings.Join(p, ",")
+	}
+
+	sortTopProcesses(titles, processes, topSortCPU)
+	if pids() != "9,100,10" {
+		t.Errorf("Expected highest CPU first, got %s", pids())
+	}
+	sortTopProcesses(titles, processes, topSortMem)
+	if pids() != "100,10,9" {
+		t.Errorf("Expected highest memory first, got %s", pids())
+	}
+	sortTopProcesses(titles, processes, topSortPID)
+	if pids() != "9,10,100" {
+		t.Errorf("Expected numeric PID order, got %s", pids())
+	}
+
+	// ps -ef has no %CPU column, so the order is left alone
+	sortTopProcesses([]string{"UID", "PID", "CMD"}, processes, topSortCPU)
+	if pids() != "9,10,100" {
+		t.Errorf("Expected the order to be kept without a %%CPU column, got %s", pids())
+	}
+}
+
+// TestMatchContainerPID tests finding a host PID's process inside the container
+func TestMatchContainerPID(t *testing.T) {
+	stat := func(startTicks int) string {
+		fields := make([]string, 40)
+		for i := range fields {
+			fields[i] = "0"
+		}
+		fields[0] = "S"
+		fields[19] = strconv.Itoa(startTicks)
+		return strings.Join(fields, " ")
+	}
+	output := strings.Join([]string{
+		"1000.50",
+		"1|" + stat(10000) + "|nginx: master process nginx -g daemon off; ",
+		"7|" + stat(90000) + "|nginx: worker process ",
+		"8|" + stat(95000) + "|nginx: worker process ",
+		"9|" + stat(95000) + "|nginx: worker process ",
+	}, "\n")
+	procs := parseProcListing(output)
+	if len(procs) != 4 || procs[0].PID != "1" || procs[0].Elapsed != 900.5 {
+		t.Fatalf("Unexpected processes %+v", procs)
+	}
+
+	if pid, err := matchContainerPID(procs, "nginx: master process nginx -g daemon off;", 900); err != nil || pid != "1" {
+		t.Errorf("Expected the master process, got %q (%v)", pid, err)
+	}
+	if pid, err := matchContainerPID(procs, "nginx:  worker process", 100); err != nil || pid != "7" {
+		t.Errorf("Expected the worker started 100s ago, got %q (%v)", pid, err)
+	}
+
+	// Two workers started in the same second can't be told apart, and a
+	// process that isn't in the container is never guessed
+	if _, err := matchContainerPID(procs, "nginx: worker process", 50); err == nil {
+		t.Error("Expected ambiguous processes to be refused")
+	}
+	if _, err := matchContainerPID(procs, "postgres", -1); err == nil {
+		t.Error("Expected a missing process to be refused")
+	}
+}
+
+// TestTopView tests refreshing, sorting and the signal picker in the process list
+func TestTopView(t *testing.T) {
+	model := Model{containers: []containerInfo{{ID: "a", Name: "web", State: "running"}}, width: 120, height: 40}
+
+	if update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")}) == nil || model.currentView != viewTop || model.topArgs != defaultTopArgs {
+		t.Fatal("Expected T to open the process list with the default ps arguments")
+	}
+	titles := []string{"USER", "PID", "%CPU", "%MEM", "COMMAND"}
+	if update(&model, topLoadedMsg{id: model.topID, titles: titles, processes: [][]string{
+		{"root", "1", "0.1", "0.5", "nginx: master"},
+		{"www", "7", "9.5", "0.2", "nginx: worker"},
+	}}) == nil {
+		t.Error("Expected the next refresh to be scheduled")
+	}
+	if view := model.View(); !strings.Contains(view, "nginx: worker") || !strings.Contains(view, "ps aux") {
+		t.Errorf("Expected the processes in the view:\n%s", view)
+	}
+
+	// The cursor follows the selected PID when sorting
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if model.topSort != topSortCPU || model.selectedTopPID() != "7" || model.topCursor != 0 {
+		t.Errorf("Expected PID 7 to stay selected at the top, got %s at %d", model.selectedTopPID(), model.topCursor)
+	}
+
+	// Stale results and ticks from an earlier cycle are ignored
+	if update(&model, topTickMsg{id: model.topID - 1}) != nil {
+		t.Error("Expected a stale tick to be dropped")
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if !model.topSignalPicker || model.topSignalPID != "7" || killSignals[model.topSignal] != "SIGTERM" {
+		t.Fatal("Expected K to open the signal picker on SIGTERM for PID 7")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	if cmd := update(&model, tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || model.statusMsg != "Sending SIGHUP to PID 7..." {
+		t.Errorf("Expected enter to send SIGHUP, got %q", model.statusMsg)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	for range len(defaultTopArgs) {
+		update(&model, tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-ef")})
+	id := model.topID
+	if update(&model, tea.KeyMsg{Type: tea.KeyEnter}) == nil || model.topArgs != "-ef" || model.topID == id {
+		t.Errorf("Expected new ps arguments to restart the refresh, got %q", model.topArgs)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.currentView != viewList || update(&model, topTickMsg{id: model.topID}) != nil {
+		t.Error("Expected leaving the view to stop refreshing")
+	}
+}
+
+// TestInspectQuery tests the JSONPath-style queries of the raw inspect tab
+func TestInspectQuery(t *testing.T) {
+	var data any
+	json.Unmarshal([]byte(`{
+		"State": {"Status": "running", "Health": {"Status": "healthy"}},
+		"Mounts": [{"Source": "/srv/a"}, {"Source": "/srv/b"}],
+		"Config": {"Labels": {"com.example.tier": "web"}},
+		"NetworkSettings": {"Networks": {"front": {"IPAddress": "172.18.0.2"}, "back": {"IPAddress": "172.19.0.2"}}}
+	}`), &data)
+
+	tests := []struct {
+		query string
+		want  []any
+	}{
+		{"$.State.Health.Status", []any{"healthy"}},
+		{"state.status", []any{"running"}},
+		{"Mounts[1].Source", []any{"/srv/b"}},
+		{"Mounts[-1].Source", []any{"/srv/b"}},
+		{"Mounts[*].Source", []any{"/srv/a", "/srv/b"}},
+		{`Config.Labels["com.example.tier"]`, []any{"web"}},
+		{"NetworkSettings.Networks.*.IPAddress", []any{"172.19.0.2", "172.18.0.2"}},
+		{"State.Missing", nil},
+	}
+	for _, tt := range tests {
+		steps, err := parseQuery(tt.query)
+		if err != nil {
+			t.Errorf("parseQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got := evalQuery(data, steps); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Query %q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"Mounts[0", "State..Status", "Mounts[x]"} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("Expected %q to be rejected", query)
+		}
+	}
+}
+
+// TestInspectTabs tests the container inspect tabs and the raw JSON query box
+func TestInspectTabs(t *testing.T) {
+	info := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:    "abc123",
+			Name:  "/web",
+			State: &container.State{Status: "running", Health: &container.Health{Status: "unhealthy", FailingStreak: 3}},
+		},
+		Config: &container.Config{
+			Image:       "nginx:1.27",
+			Env:         []string{"TZ=UTC", "APP_MODE=prod"},
+			Labels:      map[string]string{"tier": "web"},
+			Healthcheck: &container.HealthConfig{Test: []string{"CMD-SHELL", "curl -f localhost"}, Interval: 30 * time.Second},
+		},
+		Mounts: []container.MountPoint{{Type: "volume", Name: "data", Destination: "/data", RW: true}},
+		NetworkSettings: &container.NetworkSettings{
+			NetworkSettingsBase: container.NetworkSettingsBase{Ports: nat.PortMap{
+				"443/tcp": nil,
+				"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "8080"}},
+			}},
+		},
+	}
+	data, _ := json.Marshal(info)
+	model := Model{currentView: viewList, width: 120, height: 40}
+
+	update(&model, inspectDataMsg{data: string(data), info: &info})
+	view := model.View()
+	if !strings.Contains(view, "Container Inspection: web") || !strings.Contains(view, "nginx:1.27") || !strings.Contains(view, "running, unhealthy") {
+		t.Errorf("Expected the overview tab:\n%s", view)
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyTab})
+	if lines := strings.Join(model.inspectTabLines(), "\n"); !strings.Contains(lines, "APP_MODE  prod") || strings.Index(lines, "APP_MODE") > strings.Index(lines, "TZ") {
+		t.Errorf("Expected a sorted env table, got:\n%s", lines)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if lines := strings.Join(model.inspectTabLines(), "\n"); !strings.Contains(lines, "80/tcp     0.0.0.0:8080") || !strings.Contains(lines, "443/tcp    (not published)") {
+		t.Errorf("Expected the ports table, got:\n%s", lines)
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7")})
+	if lines := strings.Join(model.inspectTabLines(), "\n"); !strings.Contains(lines, "curl -f localhost") || !strings.Contains(lines, "3") {
+		t.Errorf("Expected the health check details, got:\n%s", lines)
+	}
+
+	// The query box only opens on the raw tab
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if model.inspectQueryPrompt {
+		t.Error("Expected / to do nothing outside the raw tab")
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8")})
+	update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range ".Config.Image" {
+		update(&model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(string(r))})
+	}
+	update(&model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.inspectQuery != ".Config.Image" || strings.Join(model.inspectTabLines(), "") != `"nginx:1.27"` {
+		t.Errorf("Expected the query result, got %q", model.inspectTabLines())
+	}
+
+	update(&model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.currentView != viewList || model.inspectInfo != nil {
+		t.Error("Expected esc to return to the list")
+	}
+}